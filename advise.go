@@ -0,0 +1,83 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/iadams749/JumbleBerryFieldsBot/internal/advisor"
+	"github.com/iadams749/JumbleBerryFieldsBot/internal/game"
+	"github.com/iadams749/JumbleBerryFieldsBot/internal/player"
+	"github.com/iadams749/JumbleBerryFieldsBot/internal/solver"
+)
+
+// runAdvise recommends a move for a position typed in by the player, like one from a game on a physical board.
+func runAdvise(args []string) error {
+	fs := flag.NewFlagSet("advise", flag.ExitOnError)
+	dice := fs.String("dice", "", `berries in the jars, like "J J M P X" (X is a pest)`)
+	rollsLeft := fs.Int("rolls", 2, "rolls left in the turn")
+	used := fs.String("used", "", `used categories and their points, like "mixed=12,five=0"`)
+	policyName := fs.String("policy", "solver", "policy that recommends the move: solver, greedy, rules or genome")
+	path := fs.String("genome", "best_genome.json", "saved genome used by the genome policy")
+	rulesPath := fs.String("rules", "", "JSON file with house rules, the standard rules are used if empty")
+	fs.Parse(args)
+
+	rules := game.DefaultRules()
+	if *rulesPath != "" {
+		var err error
+		if rules, err = game.LoadRules(*rulesPath); err != nil {
+			return err
+		}
+	}
+
+	berries, err := game.ParseBerries(*dice)
+	if err != nil {
+		return err
+	}
+
+	usedCats, err := advisor.ParseUsed(*used)
+	if err != nil {
+		return err
+	}
+
+	gs, err := game.NewGameFromPosition(rules, game.Position{Berries: berries, RollsLeft: *rollsLeft, Used: usedCats})
+	if err != nil {
+		return err
+	}
+
+	s := solver.New(rules)
+
+	var policy player.Player
+	switch *policyName {
+	case "solver":
+		policy = player.Func(s.BestAction)
+	case "greedy":
+		policy = player.Greedy{}
+	case "rules":
+		policy = player.RuleBased{}
+	case "genome":
+		if *rulesPath != "" {
+			return fmt.Errorf("the genome policy only plays the standard rules")
+		}
+
+		network, err := loadNetwork(*path)
+		if err != nil {
+			return err
+		}
+		policy = network
+	default:
+		return fmt.Errorf("unknown policy %q", *policyName)
+	}
+
+	advice, err := advisor.Advise(gs, policy, s)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Recommended by %s: %s (expected final score %.2f)\n\n", *policyName, advice.Recommended.Description, advice.Recommended.Value)
+	fmt.Println("Expected final score of every choice, playing optimally afterwards:")
+	for _, alt := range advice.Alternatives {
+		fmt.Printf("  %7.2f  %s\n", alt.Value, alt.Description)
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/iadams749/JumbleBerryFieldsBot/internal/arena"
+)
+
+// runArena plays every listed player over the same seeded games and compares them.
+func runArena(args []string) error {
+	fs := flag.NewFlagSet("arena", flag.ExitOnError)
+	list := fs.String("players", "random,greedy,rules,genome", "comma separated players: "+playerNames+", or the path of a saved genome")
+	path := fs.String("genome", "best_genome.json", "saved genome used by the genome player")
+	games := fs.Int("games", 1000, "number of games each player plays")
+	seed := fs.Int64("seed", 1, "seed of the first game, game i uses seed+i")
+	jsonPath := fs.String("json", "", "also write the report and every score to this JSON file")
+	fs.Parse(args)
+
+	var entrants []arena.Entrant
+	for _, name := range strings.Split(*list, ",") {
+		name = strings.TrimSpace(name)
+
+		kind, genomePath := name, *path
+		if strings.HasSuffix(name, ".json") {
+			kind, genomePath = "genome", name
+		}
+
+		p, err := newPlayer(kind, genomePath, *seed)
+		if err != nil {
+			return err
+		}
+		entrants = append(entrants, arena.Entrant{Name: name, Player: p})
+	}
+
+	report, err := arena.Run(entrants, *games, *seed)
+	if err != nil {
+		return err
+	}
+
+	if err := report.Write(os.Stdout); err != nil {
+		return err
+	}
+
+	if *jsonPath != "" {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		if err := os.WriteFile(*jsonPath, data, 0o644); err != nil {
+			return fmt.Errorf("error writing report: %w", err)
+		}
+	}
+
+	return nil
+}
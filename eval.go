@@ -0,0 +1,50 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/iadams749/JumbleBerryFieldsBot/internal/arena"
+)
+
+// runEval scores a saved genome, or one of the baseline players, over many seeded games.
+func runEval(args []string) error {
+	fs := flag.NewFlagSet("eval", flag.ExitOnError)
+	name := fs.String("player", "genome", "player to evaluate: "+playerNames)
+	path := fs.String("genome", "best_genome.json", "saved genome to evaluate")
+	games := fs.Int("games", 1000, "number of games to play")
+	seed := fs.Int64("seed", 1, "seed of the first game, game i uses seed+i")
+	categories := fs.Bool("categories", false, "break the scores down by category and the round each category was used")
+	fs.Parse(args)
+
+	p, err := newPlayer(*name, *path, *seed)
+	if err != nil {
+		return err
+	}
+
+	analysis, err := arena.Analyze(arena.Entrant{Name: *name, Player: p}, *games, *seed)
+	if err != nil {
+		return err
+	}
+
+	if *categories {
+		return analysis.Write(os.Stdout)
+	}
+
+	printStats(analysis.Scores)
+
+	return nil
+}
+
+// printStats prints the mean with its confidence interval, the median, standard deviation, minimum and maximum of the scores.
+func printStats(scores []int) {
+	if len(scores) == 0 {
+		fmt.Println("no games played")
+		return
+	}
+
+	s := arena.Summarize(scores)
+	fmt.Printf("Games: %d | Mean: %.2f [%.2f, %.2f] | Median: %.1f | Std: %.2f | Min: %d | Max: %d\n",
+		s.Games, s.Mean, s.CILow, s.CIHigh, s.Median, s.Std, s.Min, s.Max)
+}
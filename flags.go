@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/iadams749/JumbleBerryFieldsBot/internal/genome"
+	"github.com/iadams749/JumbleBerryFieldsBot/internal/player"
+	"github.com/iadams749/JumbleBerryFieldsBot/internal/solver"
+)
+
+// intList is a flag.Value for a comma separated list of integers, like "128,128".
+type intList []int
+
+func (l *intList) String() string {
+	var parts []string
+	for _, n := range *l {
+		parts = append(parts, strconv.Itoa(n))
+	}
+
+	return strings.Join(parts, ",")
+}
+
+func (l *intList) Set(value string) error {
+	*l = nil
+	if value == "" {
+		return nil
+	}
+
+	for _, part := range strings.Split(value, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || n < 1 {
+			return fmt.Errorf("invalid layer size %q", part)
+		}
+		*l = append(*l, n)
+	}
+
+	return nil
+}
+
+// activationList is a flag.Value for a comma separated list of activations, like "relu,relu,identity".
+type activationList []genome.Activation
+
+func (l *activationList) String() string {
+	var parts []string
+	for _, act := range *l {
+		parts = append(parts, string(act))
+	}
+
+	return strings.Join(parts, ",")
+}
+
+func (l *activationList) Set(value string) error {
+	*l = nil
+	if value == "" {
+		return nil
+	}
+
+	for _, part := range strings.Split(value, ",") {
+		*l = append(*l, genome.Activation(strings.TrimSpace(part)))
+	}
+
+	return nil
+}
+
+// loadNetwork loads the genome saved at path, of either kind, and builds its network.
+func loadNetwork(path string) (*genome.Network, error) {
+	network, _, err := genome.LoadNetwork(path)
+	return network, err
+}
+
+// playerNames lists the players newPlayer can create, for flag help.
+const playerNames = "genome, random, greedy, rules or solver"
+
+// newPlayer returns the named player. The genome player loads the genome saved at path,
+// and the random player draws its choices from seed.
+func newPlayer(name, path string, seed int64) (player.Player, error) {
+	switch name {
+	case "genome":
+		network, err := loadNetwork(path)
+		if err != nil {
+			return nil, err
+		}
+		return network, nil
+	case "random":
+		return player.NewRandom(seed), nil
+	case "greedy":
+		return player.Greedy{}, nil
+	case "rules":
+		return player.RuleBased{}, nil
+	case "solver":
+		return player.Func(solver.New(nil).BestAction), nil
+	default:
+		return nil, fmt.Errorf("unknown player %q, expected %s", name, playerNames)
+	}
+}
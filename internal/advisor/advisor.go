@@ -0,0 +1,146 @@
+// Package advisor recommends moves for a game of Jumbleberry Fields, like one being played on a physical board.
+package advisor
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/iadams749/JumbleBerryFieldsBot/internal/game"
+	"github.com/iadams749/JumbleBerryFieldsBot/internal/player"
+	"github.com/iadams749/JumbleBerryFieldsBot/internal/solver"
+)
+
+// An Alternative is a legal action with the final score it is expected to lead to when playing optimally afterwards.
+type Alternative struct {
+	Action      game.Action
+	Description string
+	Value       float64
+}
+
+// Advice is the action a player recommends, and the value of every alternative.
+type Advice struct {
+	Recommended Alternative
+
+	// Alternatives holds every distinct legal action, from the highest expected score to the lowest.
+	Alternatives []Alternative
+}
+
+// Advise asks the player for an action in the game state, and uses the solver to value it and every alternative.
+func Advise(gs *game.GameState, p player.Player, s *solver.Solver) (*Advice, error) {
+	values, err := s.Evaluate(gs)
+	if err != nil {
+		return nil, err
+	}
+
+	action, err := p.ChooseAction(gs)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := gs.CheckAction(action); err != nil {
+		return nil, fmt.Errorf("player recommended an illegal action %s: %w", action, err)
+	}
+
+	advice := &Advice{Recommended: Alternative{Action: action, Description: Describe(gs, action)}}
+	for _, v := range values {
+		alt := Alternative{Action: v.Action, Description: Describe(gs, v.Action), Value: v.Value}
+		advice.Alternatives = append(advice.Alternatives, alt)
+
+		if sameChoice(gs, action, v.Action) {
+			advice.Recommended.Value = v.Value
+		}
+	}
+
+	return advice, nil
+}
+
+// sameChoice returns true if two actions have the same effect: scoring the same category,
+// or rerolling while keeping the same berries.
+func sameChoice(gs *game.GameState, a, b game.Action) bool {
+	if a.Kind != b.Kind {
+		return false
+	}
+
+	if a.Kind == game.Score {
+		return a.Category == b.Category
+	}
+
+	counts := make(map[game.Berry]int)
+	for i, berry := range gs.GetBerries() {
+		if a.Keeps(i) {
+			counts[berry]++
+		}
+		if b.Keeps(i) {
+			counts[berry]--
+		}
+	}
+
+	for _, n := range counts {
+		if n != 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Describe returns a description of the action for a person at the table, with the jars numbered from 1.
+func Describe(gs *game.GameState, a game.Action) string {
+	if a.Kind == game.Score {
+		cat := gs.Categories.Get(a.Category)
+		if cat == nil {
+			return a.String()
+		}
+
+		return fmt.Sprintf("score %s for %d points", a.Category, cat.PreviewScore(gs.GetBerries()))
+	}
+
+	var jars, names []string
+	for i, jar := range gs.Jars {
+		if a.Keeps(i) {
+			jars = append(jars, strconv.Itoa(i+1))
+			names = append(names, jar.Berry.Name())
+		}
+	}
+
+	switch len(jars) {
+	case 0:
+		return "reroll every jar"
+	case len(gs.Jars):
+		return "keep every jar and use up a roll"
+	default:
+		return fmt.Sprintf("keep jars %s (%s) and reroll the rest", strings.Join(jars, " "), strings.Join(names, " "))
+	}
+}
+
+// ParseUsed parses a comma separated list of used categories, each optionally followed by the points it scored,
+// like "mixed=12, five=0, free".
+func ParseUsed(s string) (map[game.CategoryID]int, error) {
+	used := make(map[game.CategoryID]int)
+	if strings.TrimSpace(s) == "" {
+		return used, nil
+	}
+
+	for _, entry := range strings.Split(s, ",") {
+		name, points, hasPoints := strings.Cut(entry, "=")
+
+		id, err := game.ParseCategory(name)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, ok := used[id]; ok {
+			return nil, fmt.Errorf("%s is listed more than once", id)
+		}
+
+		used[id] = 0
+		if hasPoints {
+			if used[id], err = strconv.Atoi(strings.TrimSpace(points)); err != nil {
+				return nil, fmt.Errorf("invalid points %q for %s", points, id)
+			}
+		}
+	}
+
+	return used, nil
+}
@@ -0,0 +1,143 @@
+package advisor
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/iadams749/JumbleBerryFieldsBot/internal/game"
+	"github.com/iadams749/JumbleBerryFieldsBot/internal/player"
+	"github.com/iadams749/JumbleBerryFieldsBot/internal/solver"
+)
+
+// defaultSolver is shared between tests because solving the game takes a moment.
+var defaultSolver = solver.New(nil)
+
+// position returns a game at the provided position, failing the test if it's invalid.
+func position(t *testing.T, dice string, rollsLeft int, used string) *game.GameState {
+	t.Helper()
+	berries, err := game.ParseBerries(dice)
+	if err != nil {
+		t.Fatalf("ParseBerries() error = %v", err)
+	}
+
+	usedCats, err := ParseUsed(used)
+	if err != nil {
+		t.Fatalf("ParseUsed() error = %v", err)
+	}
+
+	gs, err := game.NewGameFromPosition(nil, game.Position{Berries: berries, RollsLeft: rollsLeft, Used: usedCats})
+	if err != nil {
+		t.Fatalf("NewGameFromPosition() error = %v", err)
+	}
+
+	return gs
+}
+
+func TestAdvise(t *testing.T) {
+	t.Parallel()
+	gs := position(t, "M M M X S", 2, "mixed=12")
+
+	advice, err := Advise(gs, player.Func(defaultSolver.BestAction), defaultSolver)
+	if err != nil {
+		t.Fatalf("Advise() error = %v", err)
+	}
+
+	best := advice.Alternatives[0]
+	if advice.Recommended.Action != best.Action || advice.Recommended.Value != best.Value {
+		t.Errorf("the solver recommended %+v, but the best alternative is %+v", advice.Recommended, best)
+	}
+
+	for i := 1; i < len(advice.Alternatives); i++ {
+		if advice.Alternatives[i].Value > advice.Alternatives[i-1].Value {
+			t.Fatalf("alternatives aren't sorted by value")
+		}
+	}
+
+	// a greedy recommendation is valued even though it isn't the best
+	greedy, err := Advise(gs, player.Greedy{}, defaultSolver)
+	if err != nil {
+		t.Fatalf("Advise() error = %v", err)
+	}
+
+	if greedy.Recommended.Value <= 12 || greedy.Recommended.Value >= best.Value {
+		t.Errorf("greedy recommendation %+v has an unexpected value, best is %v", greedy.Recommended, best.Value)
+	}
+
+	if _, err := Advise(gs, player.Func(func(*game.GameState) (game.Action, error) { return game.NewScore(game.CatMixed), nil }), defaultSolver); err == nil {
+		t.Errorf("Advise() expected an error for an illegal recommendation")
+	}
+}
+
+func TestAdvise_MatchesRerollsByBerries(t *testing.T) {
+	t.Parallel()
+	gs := position(t, "M S M X X", 1, "")
+
+	// keeping jar 3 and keeping jar 1 keep the same berries, so they are worth the same
+	keepThird := player.Func(func(*game.GameState) (game.Action, error) { return game.NewReroll(2), nil })
+	advice, err := Advise(gs, keepThird, defaultSolver)
+	if err != nil {
+		t.Fatalf("Advise() error = %v", err)
+	}
+
+	var want float64
+	for _, alt := range advice.Alternatives {
+		if alt.Action == game.NewReroll(0) {
+			want = alt.Value
+		}
+	}
+
+	if want == 0 || advice.Recommended.Value != want {
+		t.Errorf("Recommended value = %v, want %v", advice.Recommended.Value, want)
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	t.Parallel()
+	gs := position(t, "M M M X S", 1, "")
+	tests := []struct {
+		name   string
+		action game.Action
+		want   string
+	}{
+		{name: "Score", action: game.NewScore(game.CatThree), want: "score Three of a Kind for 23 points"},
+		{name: "Reroll everything", action: game.NewReroll(), want: "reroll every jar"},
+		{name: "Keep some", action: game.NewReroll(0, 4), want: "keep jars 1 5 (Moonberry Sugarberry) and reroll the rest"},
+		{name: "Keep everything", action: game.NewReroll(0, 1, 2, 3, 4), want: "keep every jar and use up a roll"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := Describe(gs, tt.action); got != tt.want {
+				t.Errorf("Describe() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseUsed(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		input   string
+		want    map[game.CategoryID]int
+		wantErr bool
+	}{
+		{name: "Empty", input: "", want: map[game.CategoryID]int{}},
+		{name: "Names and points", input: "mixed=12, five=0,free", want: map[game.CategoryID]int{game.CatMixed: 12, game.CatFive: 0, game.CatFree: 0}},
+		{name: "Unknown category", input: "mixed, bonus", wantErr: true},
+		{name: "Invalid points", input: "mixed=lots", wantErr: true},
+		{name: "Duplicate", input: "mixed, Mixed Basket", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := ParseUsed(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseUsed() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseUsed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
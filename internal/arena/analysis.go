@@ -0,0 +1,138 @@
+package arena
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/iadams749/JumbleBerryFieldsBot/internal/game"
+	"github.com/iadams749/JumbleBerryFieldsBot/internal/player"
+)
+
+// CategoryStats describes how a player used one category over many games.
+type CategoryStats struct {
+	Category game.CategoryID `json:"category"`
+
+	// Points counts the games the category was scored with each number of points.
+	Points  map[int]int `json:"points"`
+	Summary Summary     `json:"summary"`
+
+	// Zeroed is the number of games the category was scored with zero points.
+	Zeroed int `json:"zeroed"`
+
+	// Rounds counts the games the category was used in each round, Rounds[0] is the first round.
+	Rounds    [game.NumCategories]int `json:"rounds"`
+	MeanRound float64                 `json:"meanRound"`
+}
+
+// An Analysis breaks down the scores of one player over many games by category.
+type Analysis struct {
+	Name string `json:"name"`
+
+	// Game i is played with dice seeded with Seed+i.
+	Games int   `json:"games"`
+	Seed  int64 `json:"seed"`
+
+	Scores  []int   `json:"scores"`
+	Summary Summary `json:"summary"`
+
+	Categories [game.NumCategories]CategoryStats `json:"categories"`
+}
+
+// Analyze plays the provided number of games with the entrant, drawing the rolls of game i from a Roller seeded
+// with seed+i, and records the points and round of every category.
+func Analyze(e Entrant, games int, seed int64) (*Analysis, error) {
+	if games < 1 {
+		return nil, fmt.Errorf("the analysis needs at least one game, got %d", games)
+	}
+
+	a := &Analysis{Name: e.Name, Games: games, Seed: seed, Scores: make([]int, games)}
+	points := make([][]int, game.NumCategories)
+	for id := range a.Categories {
+		a.Categories[id] = CategoryStats{Category: game.CategoryID(id), Points: make(map[int]int)}
+	}
+
+	for i := range games {
+		gs := game.NewGameWithRoller(game.DefaultRules(), game.NewSeededRoller(seed+int64(i)))
+		if err := gs.EnableLog(); err != nil {
+			return nil, err
+		}
+		gs.RollJars()
+
+		if err := player.PlayGame(e.Player, gs); err != nil {
+			return nil, fmt.Errorf("%s, game %d: %w", e.Name, i, err)
+		}
+
+		a.Scores[i] = gs.Score
+		for _, event := range gs.Log.Events {
+			if event.Kind != game.EventScore {
+				continue
+			}
+
+			stats := &a.Categories[event.Category]
+			stats.Points[event.Points]++
+			stats.Rounds[event.Round-1]++
+			points[event.Category] = append(points[event.Category], event.Points)
+			if event.Points == 0 {
+				stats.Zeroed++
+			}
+		}
+	}
+
+	a.Summary = Summarize(a.Scores)
+	for id := range a.Categories {
+		stats := &a.Categories[id]
+		stats.Summary = Summarize(points[id])
+
+		for round, n := range stats.Rounds {
+			stats.MeanRound += float64((round+1)*n) / float64(games)
+		}
+	}
+
+	return a, nil
+}
+
+// Write prints the analysis as tables for a terminal.
+func (a *Analysis) Write(w io.Writer) error {
+	var b strings.Builder
+
+	s := a.Summary
+	fmt.Fprintf(&b, "%s over %d games, dice seeded from %d\n", a.Name, a.Games, a.Seed)
+	fmt.Fprintf(&b, "Score: mean %.2f [%.2f, %.2f], median %.1f, std %.2f, min %d, max %d\n\n",
+		s.Mean, s.CILow, s.CIHigh, s.Median, s.Std, s.Min, s.Max)
+
+	fmt.Fprintf(&b, "%-15s %6s %6s %6s %4s %4s %6s %6s   %s\n", "category", "mean", "std", "median", "min", "max", "zero", "round", "used in round 1 to 9")
+	for _, stats := range a.Categories {
+		cs := stats.Summary
+		fmt.Fprintf(&b, "%-15s %6.2f %6.2f %6.1f %4d %4d %5.1f%% %6.2f  ",
+			stats.Category, cs.Mean, cs.Std, cs.Median, cs.Min, cs.Max, a.percent(stats.Zeroed), stats.MeanRound)
+		for _, n := range stats.Rounds {
+			fmt.Fprintf(&b, " %3.0f%%", a.percent(n))
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\nPoints scored in each category, as points: share of games\n")
+	for _, stats := range a.Categories {
+		values := make([]int, 0, len(stats.Points))
+		for points := range stats.Points {
+			values = append(values, points)
+		}
+		slices.Sort(values)
+
+		var parts []string
+		for _, points := range values {
+			parts = append(parts, fmt.Sprintf("%d: %.1f%%", points, a.percent(stats.Points[points])))
+		}
+		fmt.Fprintf(&b, "%-15s %s\n", stats.Category, strings.Join(parts, ", "))
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// percent returns n as a percentage of the games.
+func (a *Analysis) percent(n int) float64 {
+	return 100 * float64(n) / float64(a.Games)
+}
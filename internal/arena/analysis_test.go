@@ -0,0 +1,81 @@
+package arena
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/iadams749/JumbleBerryFieldsBot/internal/game"
+	"github.com/iadams749/JumbleBerryFieldsBot/internal/player"
+)
+
+func TestAnalyze(t *testing.T) {
+	t.Parallel()
+	a, err := Analyze(Entrant{Name: "rules", Player: player.RuleBased{}}, 40, 3)
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+
+	categoryMeans := 0.0
+	for _, stats := range a.Categories {
+		used, scored := 0, 0
+		for _, n := range stats.Rounds {
+			used += n
+		}
+		for _, n := range stats.Points {
+			scored += n
+		}
+
+		if used != a.Games || scored != a.Games {
+			t.Errorf("%s was used in %d games and scored in %d, want %d", stats.Category, used, scored, a.Games)
+		}
+
+		if stats.Zeroed != stats.Points[0] {
+			t.Errorf("%s Zeroed = %d, but it scored 0 points %d times", stats.Category, stats.Zeroed, stats.Points[0])
+		}
+
+		if stats.MeanRound < 1 || stats.MeanRound > game.NumCategories {
+			t.Errorf("%s MeanRound = %v", stats.Category, stats.MeanRound)
+		}
+
+		categoryMeans += stats.Summary.Mean
+	}
+
+	if math.Abs(categoryMeans-a.Summary.Mean) > 1e-9 {
+		t.Errorf("the category means add up to %v, want the mean score %v", categoryMeans, a.Summary.Mean)
+	}
+
+	var out strings.Builder
+	if err := a.Write(&out); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	for _, want := range []string{"rules over 40 games", "Five of a Kind", "used in round 1 to 9", "Mixed Basket    "} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Write() = %s, doesn't contain %q", out.String(), want)
+		}
+	}
+}
+
+func TestAnalyze_Rounds(t *testing.T) {
+	t.Parallel()
+	// scores the categories in scorecard order, so category i is always used in round i+1
+	inOrder := player.Func(func(gs *game.GameState) (game.Action, error) {
+		return game.NewScore(game.CategoryID(gs.RoundsCompleted)), nil
+	})
+
+	a, err := Analyze(Entrant{Name: "in order", Player: inOrder}, 5, 1)
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+
+	for id, stats := range a.Categories {
+		if stats.Rounds[id] != 5 || stats.MeanRound != float64(id+1) {
+			t.Errorf("%s Rounds = %v and MeanRound = %v, want every game in round %d", stats.Category, stats.Rounds, stats.MeanRound, id+1)
+		}
+	}
+
+	if _, err := Analyze(Entrant{Name: "in order", Player: inOrder}, 0, 1); err == nil {
+		t.Errorf("Analyze() expected an error without games")
+	}
+}
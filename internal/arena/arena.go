@@ -0,0 +1,129 @@
+// Package arena benchmarks players against each other over many games played with the same seeded dice.
+package arena
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/iadams749/JumbleBerryFieldsBot/internal/game"
+	"github.com/iadams749/JumbleBerryFieldsBot/internal/player"
+)
+
+// An Entrant is a named player taking part in the arena.
+type Entrant struct {
+	Name   string
+	Player player.Player
+}
+
+// A Result is how one entrant did over every game.
+type Result struct {
+	Name    string  `json:"name"`
+	Scores  []int   `json:"scores"`
+	Summary Summary `json:"summary"`
+
+	// Zeroed counts the games each category was scored with zero points, in scorecard order.
+	Zeroed [game.NumCategories]int `json:"zeroed"`
+}
+
+// A Report is the outcome of an arena run.
+type Report struct {
+	// Game i of every entrant is played with dice seeded with Seed+i.
+	Games int   `json:"games"`
+	Seed  int64 `json:"seed"`
+
+	Results []Result `json:"results"`
+
+	// Comparisons pairs up every two entrants, in the order they were entered.
+	Comparisons []Comparison `json:"comparisons"`
+}
+
+// Run plays the provided number of games with every entrant and compares the results.
+// Game i of every entrant draws its rolls from a Roller seeded with seed+i, so the entrants face the same dice
+// for as long as they make the same number of rolls.
+func Run(entrants []Entrant, games int, seed int64) (*Report, error) {
+	if len(entrants) == 0 {
+		return nil, fmt.Errorf("the arena needs at least one player")
+	}
+
+	if games < 1 {
+		return nil, fmt.Errorf("the arena needs at least one game, got %d", games)
+	}
+
+	report := &Report{Games: games, Seed: seed}
+	for _, e := range entrants {
+		a, err := Analyze(e, games, seed)
+		if err != nil {
+			return nil, err
+		}
+
+		result := Result{Name: e.Name, Scores: a.Scores, Summary: a.Summary}
+		for id, stats := range a.Categories {
+			result.Zeroed[id] = stats.Zeroed
+		}
+		report.Results = append(report.Results, result)
+	}
+
+	for i, a := range report.Results {
+		for _, b := range report.Results[i+1:] {
+			report.Comparisons = append(report.Comparisons, Compare(a.Name, a.Scores, b.Name, b.Scores))
+		}
+	}
+
+	return report, nil
+}
+
+// Write prints the report as tables for a terminal.
+func (r *Report) Write(w io.Writer) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%d games per player, dice seeded from %d\n\n", r.Games, r.Seed)
+
+	width := len("player")
+	for _, res := range r.Results {
+		width = max(width, len(res.Name))
+	}
+
+	fmt.Fprintf(&b, "%-*s %8s %17s %7s %6s %4s %6s %6s %6s %6s %4s\n", width, "player", "mean", "95% CI", "median", "std", "min", "p5", "p25", "p75", "p95", "max")
+	for _, res := range r.Results {
+		s := res.Summary
+		fmt.Fprintf(&b, "%-*s %8.2f [%6.2f, %6.2f] %7.1f %6.2f %4d %6.1f %6.1f %6.1f %6.1f %4d\n",
+			width, res.Name, s.Mean, s.CILow, s.CIHigh, s.Median, s.Std, s.Min, s.P5, s.P25, s.P75, s.P95, s.Max)
+	}
+
+	fmt.Fprintf(&b, "\nGames each category was scored with zero points\n%-*s", width, "player")
+	for id := range game.CategoryID(game.NumCategories) {
+		fmt.Fprintf(&b, " %7s", categoryAbbreviations[id])
+	}
+	b.WriteString("\n")
+	for _, res := range r.Results {
+		fmt.Fprintf(&b, "%-*s", width, res.Name)
+		for _, zeroed := range res.Zeroed {
+			fmt.Fprintf(&b, " %6.1f%%", 100*float64(zeroed)/float64(r.Games))
+		}
+		b.WriteString("\n")
+	}
+
+	if len(r.Comparisons) > 0 {
+		b.WriteString("\nPaired comparisons on the same dice\n")
+		for _, c := range r.Comparisons {
+			verdict := "no significant difference"
+			if c.Significant() {
+				better, worse := c.A, c.B
+				if c.MeanDiff < 0 {
+					better, worse = c.B, c.A
+				}
+				verdict = fmt.Sprintf("%s beats %s", better, worse)
+			}
+
+			fmt.Fprintf(&b, "%s vs %s: %+.2f points per game [%+.2f, %+.2f], %d wins %d ties %d losses, %s\n",
+				c.A, c.B, c.MeanDiff, c.CILow, c.CIHigh, c.Wins, c.Ties, c.Losses, verdict)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// categoryAbbreviations are short names for the categories that fit in a table column.
+var categoryAbbreviations = [game.NumCategories]string{"jumble", "sugar", "pickle", "moon", "three", "four", "five", "mixed", "free"}
@@ -0,0 +1,78 @@
+package arena
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/iadams749/JumbleBerryFieldsBot/internal/game"
+	"github.com/iadams749/JumbleBerryFieldsBot/internal/player"
+)
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+	entrants := []Entrant{
+		{Name: "greedy", Player: player.Greedy{}},
+		{Name: "rules", Player: player.RuleBased{}},
+		{Name: "greedy again", Player: player.Greedy{}},
+	}
+
+	report, err := Run(entrants, 50, 7)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if len(report.Results) != 3 || len(report.Comparisons) != 3 {
+		t.Fatalf("Run() returned %d results and %d comparisons, want 3 and 3", len(report.Results), len(report.Comparisons))
+	}
+
+	// the same player faces the same dice, so it scores the same
+	if !reflect.DeepEqual(report.Results[0].Scores, report.Results[2].Scores) {
+		t.Errorf("the same player scored differently on the same dice")
+	}
+
+	same := report.Comparisons[1]
+	if same.A != "greedy" || same.B != "greedy again" || same.Ties != 50 || same.Significant() {
+		t.Errorf("comparison of a player with itself = %+v, want 50 ties", same)
+	}
+
+	if rules := report.Comparisons[2]; rules.A != "rules" || rules.MeanDiff <= 0 || !rules.Significant() {
+		t.Errorf("rules vs greedy = %+v, want rules to be significantly better", rules)
+	}
+
+	// greedy never rerolls, so it rarely has five of a kind
+	if zeroed := report.Results[0].Zeroed[game.CatFive]; zeroed < 45 {
+		t.Errorf("greedy zeroed five of a kind %d times in 50 games", zeroed)
+	}
+
+	if zeroed := report.Results[0].Zeroed[game.CatFree]; zeroed != 0 {
+		t.Errorf("greedy zeroed the free roll %d times, but it always scores", zeroed)
+	}
+
+	var out strings.Builder
+	if err := report.Write(&out); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	for _, want := range []string{"greedy again", "95% CI", "mixed", "rules beats greedy", "greedy vs greedy again"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Write() = %s, doesn't contain %q", out.String(), want)
+		}
+	}
+}
+
+func TestRun_Invalid(t *testing.T) {
+	t.Parallel()
+	if _, err := Run(nil, 10, 1); err == nil {
+		t.Errorf("Run() expected an error without entrants")
+	}
+
+	if _, err := Run([]Entrant{{Name: "greedy", Player: player.Greedy{}}}, 0, 1); err == nil {
+		t.Errorf("Run() expected an error without games")
+	}
+
+	stuck := player.Func(func(*game.GameState) (game.Action, error) { return game.NewScore(game.CatFree), nil })
+	if _, err := Run([]Entrant{{Name: "stuck", Player: stuck}}, 1, 1); err == nil {
+		t.Errorf("Run() expected an error for a player that makes an illegal move")
+	}
+}
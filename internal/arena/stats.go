@@ -0,0 +1,149 @@
+package arena
+
+import (
+	"math"
+	"sort"
+)
+
+// z95 is the critical value of the normal distribution for a two sided 95% confidence interval.
+const z95 = 1.96
+
+// A Summary describes the distribution of the scores of one player.
+type Summary struct {
+	Games  int     `json:"games"`
+	Mean   float64 `json:"mean"`
+	Median float64 `json:"median"`
+
+	// Std is the sample standard deviation.
+	Std float64 `json:"std"`
+	Min int     `json:"min"`
+	Max int     `json:"max"`
+
+	// P5, P25, P75 and P95 are percentiles of the scores, interpolated between the closest games.
+	P5  float64 `json:"p5"`
+	P25 float64 `json:"p25"`
+	P75 float64 `json:"p75"`
+	P95 float64 `json:"p95"`
+
+	// CILow and CIHigh bound the 95% confidence interval of the mean, using a normal approximation.
+	CILow  float64 `json:"ciLow"`
+	CIHigh float64 `json:"ciHigh"`
+}
+
+// Summarize returns the summary of the scores. The scores aren't modified.
+func Summarize(scores []int) Summary {
+	s := Summary{Games: len(scores)}
+	if len(scores) == 0 {
+		return s
+	}
+
+	sorted := make([]float64, len(scores))
+	for i, score := range scores {
+		sorted[i] = float64(score)
+	}
+	sort.Float64s(sorted)
+
+	s.Mean, s.Std = meanStd(sorted)
+	s.Min, s.Max = int(sorted[0]), int(sorted[len(sorted)-1])
+	s.Median = percentile(sorted, 50)
+	s.P5, s.P25 = percentile(sorted, 5), percentile(sorted, 25)
+	s.P75, s.P95 = percentile(sorted, 75), percentile(sorted, 95)
+	s.CILow, s.CIHigh = confidenceInterval(s.Mean, s.Std, len(scores))
+
+	return s
+}
+
+// A Comparison is the result of pairing the games two players played with the same dice.
+type Comparison struct {
+	A string `json:"a"`
+	B string `json:"b"`
+
+	// Wins, Ties and Losses count the games from the point of view of A.
+	Wins   int `json:"wins"`
+	Ties   int `json:"ties"`
+	Losses int `json:"losses"`
+
+	// MeanDiff is how many more points A scored than B per game, and CILow and CIHigh bound its 95% confidence interval.
+	// Pairing the games removes the luck of the dice they share, so the interval is narrower than comparing two Summaries.
+	MeanDiff float64 `json:"meanDiff"`
+	CILow    float64 `json:"ciLow"`
+	CIHigh   float64 `json:"ciHigh"`
+}
+
+// Significant returns true if the confidence interval of the difference doesn't include zero.
+func (c Comparison) Significant() bool {
+	return c.CILow > 0 || c.CIHigh < 0
+}
+
+// Compare pairs a[i] with b[i], which must be scores of games played with the same dice.
+func Compare(nameA string, a []int, nameB string, b []int) Comparison {
+	c := Comparison{A: nameA, B: nameB}
+
+	n := min(len(a), len(b))
+	diffs := make([]float64, n)
+	for i := range n {
+		diffs[i] = float64(a[i] - b[i])
+		switch {
+		case a[i] > b[i]:
+			c.Wins++
+		case a[i] < b[i]:
+			c.Losses++
+		default:
+			c.Ties++
+		}
+	}
+
+	var std float64
+	c.MeanDiff, std = meanStd(diffs)
+	c.CILow, c.CIHigh = confidenceInterval(c.MeanDiff, std, n)
+
+	return c
+}
+
+// meanStd returns the mean and the sample standard deviation of the values.
+func meanStd(values []float64) (float64, float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	mean := sum / float64(len(values))
+
+	if len(values) < 2 {
+		return mean, 0
+	}
+
+	variance := 0.0
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+
+	return mean, math.Sqrt(variance / float64(len(values)-1))
+}
+
+// confidenceInterval returns the bounds of the 95% confidence interval of a mean.
+func confidenceInterval(mean, std float64, n int) (float64, float64) {
+	if n == 0 {
+		return mean, mean
+	}
+
+	margin := z95 * std / math.Sqrt(float64(n))
+	return mean - margin, mean + margin
+}
+
+// percentile returns the p-th percentile of the sorted values, interpolating linearly between the closest ranks.
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := min(lower+1, len(sorted)-1)
+	frac := rank - float64(lower)
+
+	return sorted[lower] + frac*(sorted[upper]-sorted[lower])
+}
@@ -0,0 +1,64 @@
+package arena
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSummarize(t *testing.T) {
+	t.Parallel()
+	scores := []int{50, 10, 40, 20, 30}
+	s := Summarize(scores)
+
+	tests := []struct {
+		name string
+		got  float64
+		want float64
+	}{
+		{name: "Mean", got: s.Mean, want: 30},
+		{name: "Median", got: s.Median, want: 30},
+		{name: "Std", got: s.Std, want: math.Sqrt(250)},
+		{name: "Min", got: float64(s.Min), want: 10},
+		{name: "Max", got: float64(s.Max), want: 50},
+		{name: "P5", got: s.P5, want: 12},
+		{name: "P25", got: s.P25, want: 20},
+		{name: "P95", got: s.P95, want: 48},
+		{name: "CILow", got: s.CILow, want: 30 - 1.96*math.Sqrt(250)/math.Sqrt(5)},
+		{name: "CIHigh", got: s.CIHigh, want: 30 + 1.96*math.Sqrt(250)/math.Sqrt(5)},
+	}
+	for _, tt := range tests {
+		if math.Abs(tt.got-tt.want) > 1e-9 {
+			t.Errorf("%s = %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+
+	if scores[0] != 50 {
+		t.Errorf("Summarize() modified the scores")
+	}
+
+	if empty := Summarize(nil); empty != (Summary{}) {
+		t.Errorf("Summarize(nil) = %+v, want the zero Summary", empty)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	t.Parallel()
+	c := Compare("a", []int{10, 20, 30, 40}, "b", []int{8, 18, 30, 37})
+
+	if c.Wins != 3 || c.Ties != 1 || c.Losses != 0 {
+		t.Errorf("Compare() = %d wins %d ties %d losses, want 3 1 0", c.Wins, c.Ties, c.Losses)
+	}
+
+	if math.Abs(c.MeanDiff-1.75) > 1e-9 {
+		t.Errorf("MeanDiff = %v, want 1.75", c.MeanDiff)
+	}
+
+	if !c.Significant() {
+		t.Errorf("Significant() = false for [%v, %v]", c.CILow, c.CIHigh)
+	}
+
+	noisy := Compare("a", []int{10, 20, 30}, "b", []int{20, 10, 30})
+	if noisy.MeanDiff != 0 || noisy.Significant() {
+		t.Errorf("Compare() of noisy scores = %+v, want no significant difference", noisy)
+	}
+}
@@ -0,0 +1,119 @@
+// Package console lets a person play Jumbleberry Fields in the terminal.
+package console
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/iadams749/JumbleBerryFieldsBot/internal/game"
+)
+
+// A CommandKind is the type of command a player typed.
+type CommandKind int
+
+const (
+	// Lock locks jars so they keep their berry on the next roll.
+	Lock CommandKind = iota
+
+	// Unlock unlocks jars, or every jar if none are listed.
+	Unlock
+
+	// Roll rerolls every unlocked jar.
+	Roll
+
+	// Score scores the jars in a category and starts the next turn.
+	Score
+
+	// Help prints the list of commands.
+	Help
+
+	// Quit ends the game early.
+	Quit
+)
+
+// A Command is one line of input from the player.
+type Command struct {
+	Kind CommandKind
+
+	// Jars holds the zero-based jars to lock or unlock.
+	Jars []int
+
+	// Category is the category to score.
+	Category game.CategoryID
+}
+
+// commandHelp describes every command, and is printed by the help command.
+const commandHelp = `commands:
+  lock 1 3      lock jars 1 and 3 so they are kept when rolling
+  unlock 2      unlock jar 2, or every jar if none are listed
+  roll          reroll every unlocked jar
+  score mixed   score the jars in a category, any unambiguous prefix of its name works
+  help          print this list
+  quit          end the game
+`
+
+// ParseCommand parses a line typed by the player.
+// Jars are numbered from 1 for the player, and must be between 1 and jarCount.
+func ParseCommand(line string, jarCount int) (Command, error) {
+	fields := strings.Fields(strings.ToLower(line))
+	if len(fields) == 0 {
+		return Command{}, fmt.Errorf("no command, type help for the list of commands")
+	}
+
+	verb, args := fields[0], fields[1:]
+
+	switch verb {
+	case "lock", "l":
+		jars, err := parseJars(args, jarCount)
+		if err != nil {
+			return Command{}, err
+		}
+
+		if len(jars) == 0 {
+			return Command{}, fmt.Errorf("lock needs the numbers of the jars to lock, like lock 1 3")
+		}
+
+		return Command{Kind: Lock, Jars: jars}, nil
+	case "unlock", "u":
+		jars, err := parseJars(args, jarCount)
+		if err != nil {
+			return Command{}, err
+		}
+
+		return Command{Kind: Unlock, Jars: jars}, nil
+	case "roll", "r":
+		if len(args) > 0 {
+			return Command{}, fmt.Errorf("roll doesn't take any arguments, lock the jars to keep first")
+		}
+
+		return Command{Kind: Roll}, nil
+	case "score", "s":
+		id, err := game.ParseCategory(strings.Join(args, " "))
+		if err != nil {
+			return Command{}, err
+		}
+
+		return Command{Kind: Score, Category: id}, nil
+	case "help", "h", "?":
+		return Command{Kind: Help}, nil
+	case "quit", "q", "exit":
+		return Command{Kind: Quit}, nil
+	default:
+		return Command{}, fmt.Errorf("unknown command %q, type help for the list of commands", verb)
+	}
+}
+
+// parseJars converts jar numbers typed by the player to zero-based jar indices.
+func parseJars(args []string, jarCount int) ([]int, error) {
+	var jars []int
+	for _, arg := range args {
+		n, err := strconv.Atoi(arg)
+		if err != nil || n < 1 || n > jarCount {
+			return nil, fmt.Errorf("invalid jar %q, jars are numbered 1 to %d", arg, jarCount)
+		}
+		jars = append(jars, n-1)
+	}
+
+	return jars, nil
+}
@@ -0,0 +1,49 @@
+package console
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/iadams749/JumbleBerryFieldsBot/internal/game"
+)
+
+func TestParseCommand(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		line    string
+		want    Command
+		wantErr bool
+	}{
+		{name: "Lock", line: "lock 1 3", want: Command{Kind: Lock, Jars: []int{0, 2}}},
+		{name: "Lock shorthand", line: "L 5", want: Command{Kind: Lock, Jars: []int{4}}},
+		{name: "Lock without jars", line: "lock", wantErr: true},
+		{name: "Lock jar out of range", line: "lock 6", wantErr: true},
+		{name: "Lock jar zero", line: "lock 0", wantErr: true},
+		{name: "Lock non-number", line: "lock one", wantErr: true},
+		{name: "Unlock", line: "unlock 2", want: Command{Kind: Unlock, Jars: []int{1}}},
+		{name: "Unlock everything", line: "unlock", want: Command{Kind: Unlock}},
+		{name: "Roll", line: "  roll  ", want: Command{Kind: Roll}},
+		{name: "Roll with arguments", line: "roll 1", wantErr: true},
+		{name: "Score", line: "score mixed", want: Command{Kind: Score, Category: game.CatMixed}},
+		{name: "Score full name", line: "Score Three of a Kind", want: Command{Kind: Score, Category: game.CatThree}},
+		{name: "Score ambiguous", line: "score f", wantErr: true},
+		{name: "Score without category", line: "score", wantErr: true},
+		{name: "Help", line: "help", want: Command{Kind: Help}},
+		{name: "Quit", line: "quit", want: Command{Kind: Quit}},
+		{name: "Empty", line: "", wantErr: true},
+		{name: "Unknown", line: "jump", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := ParseCommand(tt.line, 5)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseCommand() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseCommand() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package console
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/iadams749/JumbleBerryFieldsBot/internal/game"
+)
+
+// A Session plays one game with a person, reading commands from in and printing the board to out.
+type Session struct {
+	gs  *game.GameState
+	in  *bufio.Scanner
+	out io.Writer
+}
+
+// NewSession returns a Session playing the game state.
+// If the jars haven't been rolled yet, they are rolled when the session starts.
+func NewSession(gs *game.GameState, in io.Reader, out io.Writer) *Session {
+	return &Session{gs: gs, in: bufio.NewScanner(in), out: out}
+}
+
+// Run plays until the game is over, the player quits or the input ends, then prints the final scorecard.
+// It returns the final score.
+func (s *Session) Run() (int, error) {
+	if len(s.gs.Jars) > 0 && !s.gs.Jars[0].Rolled {
+		if err := s.gs.RollJars(); err != nil {
+			return s.gs.Score, err
+		}
+	}
+
+	fmt.Fprint(s.out, commandHelp)
+
+	for !s.gs.IsOver() {
+		fmt.Fprintln(s.out)
+		fmt.Fprint(s.out, Board(s.gs))
+		fmt.Fprint(s.out, "> ")
+
+		if !s.in.Scan() {
+			if err := s.in.Err(); err != nil {
+				return s.gs.Score, err
+			}
+
+			fmt.Fprintln(s.out, "\nGame ended early.")
+			break
+		}
+
+		cmd, err := ParseCommand(s.in.Text(), len(s.gs.Jars))
+		if err != nil {
+			fmt.Fprintln(s.out, err)
+			continue
+		}
+
+		if cmd.Kind == Quit {
+			fmt.Fprintln(s.out, "Game ended early.")
+			break
+		}
+
+		if err := s.do(cmd); err != nil {
+			fmt.Fprintln(s.out, err)
+		}
+	}
+
+	fmt.Fprintln(s.out)
+	fmt.Fprint(s.out, Scorecard(s.gs))
+
+	return s.gs.Score, nil
+}
+
+// do applies a command to the game, returning an error if the rules don't allow it.
+func (s *Session) do(cmd Command) error {
+	gs := s.gs
+
+	switch cmd.Kind {
+	case Lock, Unlock:
+		if gs.RollsLeftInTurn < 1 {
+			return fmt.Errorf("no rolls left this turn, score a category")
+		}
+
+		jars := cmd.Jars
+		if len(jars) == 0 {
+			for i := range gs.Jars {
+				jars = append(jars, i)
+			}
+		}
+
+		for _, jar := range jars {
+			if cmd.Kind == Lock {
+				gs.LockJar(jar)
+			} else {
+				gs.UnlockJar(jar)
+			}
+		}
+	case Roll:
+		var keep []int
+		for i, jar := range gs.Jars {
+			if jar.Locked {
+				keep = append(keep, i)
+			}
+		}
+
+		if err := gs.CheckAction(game.NewReroll(keep...)); err != nil {
+			return fmt.Errorf("can't roll: %w", err)
+		}
+
+		return gs.Apply(game.NewReroll(keep...))
+	case Score:
+		action := game.NewScore(cmd.Category)
+		if err := gs.CheckAction(action); err != nil {
+			return fmt.Errorf("can't score: %w", err)
+		}
+
+		score := gs.Categories.Get(cmd.Category).PreviewScore(gs.GetBerries())
+		if err := gs.Apply(action); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(s.out, "Scored %d in %s.\n", score, cmd.Category)
+	case Help:
+		fmt.Fprint(s.out, commandHelp)
+	}
+
+	return nil
+}
+
+// Board renders the round, the jars with their numbers, and the scorecard with what each open category would score.
+func Board(gs *game.GameState) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Round %d of %d | Rolls left: %d | Score: %d\n", min(gs.RoundsCompleted+1, game.NumCategories), game.NumCategories, gs.RollsLeftInTurn, gs.Score)
+
+	b.WriteString("Jars:")
+	for i, jar := range gs.Jars {
+		fmt.Fprintf(&b, "  %d %s", i+1, jar)
+	}
+	b.WriteString("\n")
+
+	berries := gs.GetBerries()
+	for id, cat := range gs.Categories.All() {
+		if cat.IsUsed() {
+			fmt.Fprintf(&b, "  %-16s %3d\n", game.CategoryID(id), cat.GetScore())
+		} else {
+			fmt.Fprintf(&b, "  %-16s   -  (%d now)\n", game.CategoryID(id), cat.PreviewScore(berries))
+		}
+	}
+
+	return b.String()
+}
+
+// Scorecard renders the score of every category and the total.
+func Scorecard(gs *game.GameState) string {
+	var b strings.Builder
+
+	b.WriteString("Final scorecard:\n")
+	for id, cat := range gs.Categories.All() {
+		if cat.IsUsed() {
+			fmt.Fprintf(&b, "  %-16s %3d\n", game.CategoryID(id), cat.GetScore())
+		} else {
+			fmt.Fprintf(&b, "  %-16s   -\n", game.CategoryID(id))
+		}
+	}
+	fmt.Fprintf(&b, "  %-16s %3d\n", "Total", gs.Score)
+
+	return b.String()
+}
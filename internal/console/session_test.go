@@ -0,0 +1,100 @@
+package console
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/iadams749/JumbleBerryFieldsBot/internal/game"
+)
+
+func TestSession_LockAndRoll(t *testing.T) {
+	t.Parallel()
+	roller := game.NewFixedRoller(
+		game.Moonberry, game.Moonberry, game.Moonberry, game.Pest, game.Sugarberry, // first roll
+		game.Moonberry, game.Pest, // first reroll of jars 4 and 5
+		game.Jumbleberry,                                      // second reroll of jar 5
+		game.Pest, game.Pest, game.Pest, game.Pest, game.Pest, // first roll of the next turn
+	)
+	gs := game.NewGameWithRoller(nil, roller)
+
+	input := strings.Join([]string{
+		"lock 1 2 3",
+		"roll",
+		"unlock",
+		"lock 1 2 3 4",
+		"roll",
+		"roll",       // no rolls left
+		"lock 1",     // no rolls left
+		"score five", // not five of a kind, but still legal
+		"quit",
+	}, "\n")
+
+	var out bytes.Buffer
+	score, err := NewSession(gs, strings.NewReader(input), &out).Run()
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if score != 0 || !gs.Categories.FiveCategory.Used {
+		t.Errorf("Run() = %d with Five of a Kind used %v, want 0 and true", score, gs.Categories.FiveCategory.Used)
+	}
+
+	for _, want := range []string{"can't roll: no rolls left", "no rolls left this turn", "Scored 0 in Five of a Kind.", "Game ended early.", "Final scorecard:"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output doesn't contain %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestSession_LockedJarsAreKept(t *testing.T) {
+	t.Parallel()
+	roller := game.NewFixedRoller(
+		game.Moonberry, game.Moonberry, game.Pest, game.Pest, game.Pest,
+		game.Moonberry, game.Sugarberry, game.Jumbleberry,
+	)
+	gs := game.NewGameWithRoller(nil, roller)
+
+	var out bytes.Buffer
+	if _, err := NewSession(gs, strings.NewReader("lock 1 2\nroll\nquit\n"), &out).Run(); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	want := []game.Berry{game.Moonberry, game.Moonberry, game.Moonberry, game.Sugarberry, game.Jumbleberry}
+	if got := gs.GetBerries(); !reflect.DeepEqual(got, want) {
+		t.Errorf("berries after rolling = %v, want %v", got, want)
+	}
+}
+
+func TestSession_FullGame(t *testing.T) {
+	t.Parallel()
+	var lines []string
+	for id := range game.CategoryID(game.NumCategories) {
+		lines = append(lines, "score "+id.String())
+	}
+
+	// the same game played directly through the game package
+	want := game.NewGameWithRoller(nil, game.NewSeededRoller(3))
+	want.RollJars()
+	for id := range game.CategoryID(game.NumCategories) {
+		if err := want.Apply(game.NewScore(id)); err != nil {
+			t.Fatalf("Apply() error = %v", err)
+		}
+	}
+
+	gs := game.NewGameWithRoller(nil, game.NewSeededRoller(3))
+	var out bytes.Buffer
+	score, err := NewSession(gs, strings.NewReader(strings.Join(lines, "\n")), &out).Run()
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if !gs.IsOver() || score != want.Score {
+		t.Errorf("Run() = %d with game over %v, want %d and true", score, gs.IsOver(), want.Score)
+	}
+
+	if strings.Contains(out.String(), "Game ended early.") {
+		t.Errorf("a finished game was reported as ended early")
+	}
+}
@@ -0,0 +1,291 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+)
+
+// A CategoryID identifies one of the 9 scoring categories, in scorecard order.
+type CategoryID int
+
+const (
+	// the scoring categories, in the same order as GameCategories.All
+	CatJumbleberry CategoryID = iota
+	CatSugarberry
+	CatPickleberry
+	CatMoonberry
+	CatThree
+	CatFour
+	CatFive
+	CatMixed
+	CatFree
+
+	// NumCategories is the number of scoring categories, which is also the number of rounds in a game.
+	NumCategories = 9
+)
+
+// String returns the name of the category as it is printed on the scorecard.
+func (id CategoryID) String() string {
+	switch id {
+	case CatJumbleberry:
+		return "Jumbleberry"
+	case CatSugarberry:
+		return "Sugarberry"
+	case CatPickleberry:
+		return "Pickleberry"
+	case CatMoonberry:
+		return "Moonberry"
+	case CatThree:
+		return "Three of a Kind"
+	case CatFour:
+		return "Four of a Kind"
+	case CatFive:
+		return "Five of a Kind"
+	case CatMixed:
+		return "Mixed Basket"
+	case CatFree:
+		return "Free Roll"
+	default:
+		return "Unknown Category"
+	}
+}
+
+// ParseCategory returns the category named by s, ignoring case and spaces.
+// Any unambiguous prefix of a name is accepted, so "mixed" and "three" name Mixed Basket and Three of a Kind.
+func ParseCategory(s string) (CategoryID, error) {
+	normalize := func(s string) string {
+		return strings.ToLower(strings.Join(strings.Fields(s), ""))
+	}
+
+	want := normalize(s)
+	if want == "" {
+		return 0, fmt.Errorf("no category provided")
+	}
+
+	var matches []CategoryID
+	for id := range CategoryID(NumCategories) {
+		name := normalize(id.String())
+		if name == want {
+			return id, nil
+		}
+
+		if strings.HasPrefix(name, want) {
+			matches = append(matches, id)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return 0, fmt.Errorf("unknown category %q", s)
+	case 1:
+		return matches[0], nil
+	default:
+		return 0, fmt.Errorf("category %q is ambiguous, it could be %v", s, matches)
+	}
+}
+
+// Get returns the category with the provided id, or nil if the id is invalid.
+func (gc GameCategories) Get(id CategoryID) Category {
+	if id < 0 || id >= NumCategories {
+		return nil
+	}
+
+	return gc.All()[id]
+}
+
+// An ActionKind is the type of move a player can make.
+type ActionKind int
+
+const (
+	// Reroll locks the kept jars and rolls the rest.
+	Reroll ActionKind = iota
+
+	// Score scores the current berries in a category and starts the next turn.
+	Score
+)
+
+// An Action is a single move in a game.
+// Every player type drives a game by choosing an Action and passing it to GameState.Apply.
+type Action struct {
+	Kind ActionKind
+
+	// Keep is a bitmask of the jars to lock before rerolling, bit i is jar i.
+	// It is only used by Reroll actions.
+	Keep uint
+
+	// Category is the category to score.
+	// It is only used by Score actions.
+	Category CategoryID
+}
+
+// NewReroll returns a Reroll action that keeps the jars with the provided indices.
+func NewReroll(keep ...int) Action {
+	a := Action{Kind: Reroll}
+	for _, jar := range keep {
+		a.Keep |= 1 << jar
+	}
+
+	return a
+}
+
+// NewScore returns a Score action for the provided category.
+func NewScore(id CategoryID) Action {
+	return Action{Kind: Score, Category: id}
+}
+
+// Keeps returns true if the action keeps the provided jar.
+func (a Action) Keeps(jar int) bool {
+	return a.Keep&(1<<jar) != 0
+}
+
+func (a Action) String() string {
+	if a.Kind == Score {
+		return fmt.Sprintf("score %s", a.Category)
+	}
+
+	var kept []int
+	for jar := range 64 {
+		if a.Keeps(jar) {
+			kept = append(kept, jar)
+		}
+	}
+
+	return fmt.Sprintf("reroll keeping %v", kept)
+}
+
+// IsOver returns true once every category has been scored.
+func (gs *GameState) IsOver() bool {
+	return gs.RoundsCompleted >= NumCategories
+}
+
+// MaxActionsLeft returns the most actions it can take to finish the game: every roll left in this turn and a score,
+// then every roll and a score in each of the rounds after it. Every legal action makes progress,
+// so a game that isn't over after this many actions is stuck.
+func (gs *GameState) MaxActionsLeft() int {
+	if gs.IsOver() {
+		return 0
+	}
+
+	rounds := NumCategories - gs.RoundsCompleted - 1
+	return gs.RollsLeftInTurn + 1 + rounds*gs.ruleSet().RollsPerTurn
+}
+
+// hasRolled returns true if the jars have been rolled at least once this turn.
+func (gs *GameState) hasRolled() bool {
+	return gs.RollsLeftInTurn < gs.ruleSet().RollsPerTurn
+}
+
+// CheckAction returns an error if the action isn't legal in the current state.
+func (gs *GameState) CheckAction(a Action) error {
+	if gs.IsOver() {
+		return fmt.Errorf("game is over")
+	}
+
+	switch a.Kind {
+	case Reroll:
+		if gs.RollsLeftInTurn < 1 {
+			return fmt.Errorf("no rolls left")
+		}
+
+		if a.Keep>>len(gs.Jars) != 0 {
+			return fmt.Errorf("keep mask %b refers to jars that don't exist", a.Keep)
+		}
+
+		if !gs.hasRolled() && a.Keep != 0 {
+			return fmt.Errorf("can't keep jars before they have been rolled")
+		}
+	case Score:
+		cat := gs.Categories.Get(a.Category)
+		if cat == nil {
+			return fmt.Errorf("invalid category %d", a.Category)
+		}
+
+		if cat.IsUsed() {
+			return fmt.Errorf("category %s has already been scored", a.Category)
+		}
+
+		if !gs.hasRolled() {
+			return fmt.Errorf("must have rolled once to score category")
+		}
+	default:
+		return fmt.Errorf("invalid action kind %d", a.Kind)
+	}
+
+	return nil
+}
+
+// IsLegal returns true if the action is legal in the current state, like CheckAction returning nil,
+// without building an error for illegal actions. It is meant for checking actions on every move.
+func (gs *GameState) IsLegal(a Action) bool {
+	if gs.IsOver() {
+		return false
+	}
+
+	switch a.Kind {
+	case Reroll:
+		return gs.RollsLeftInTurn >= 1 && a.Keep>>len(gs.Jars) == 0 && (gs.hasRolled() || a.Keep == 0)
+	case Score:
+		cat := gs.Categories.Get(a.Category)
+		return cat != nil && !cat.IsUsed() && gs.hasRolled()
+	default:
+		return false
+	}
+}
+
+// LegalActions returns every action that can be applied to the current state.
+// Reroll actions come first, ordered by keep mask, followed by Score actions in scorecard order.
+func (gs *GameState) LegalActions() []Action {
+	var actions []Action
+
+	if gs.IsOver() {
+		return actions
+	}
+
+	if gs.RollsLeftInTurn > 0 {
+		if gs.hasRolled() {
+			for keep := range uint(1) << len(gs.Jars) {
+				actions = append(actions, Action{Kind: Reroll, Keep: keep})
+			}
+		} else {
+			actions = append(actions, NewReroll())
+		}
+	}
+
+	if gs.hasRolled() {
+		for id := range CategoryID(NumCategories) {
+			if !gs.Categories.Get(id).IsUsed() {
+				actions = append(actions, NewScore(id))
+			}
+		}
+	}
+
+	return actions
+}
+
+// Apply validates the action and applies it to the game.
+// The game is left unchanged if the action isn't legal.
+func (gs *GameState) Apply(a Action) error {
+	return gs.apply(a, true)
+}
+
+// apply validates the action and applies it to the game.
+// A Score action rolls the jars for the next turn if rollNext is true.
+func (gs *GameState) apply(a Action, rollNext bool) error {
+	if err := gs.CheckAction(a); err != nil {
+		return fmt.Errorf("illegal action %s: %w", a, err)
+	}
+
+	if a.Kind == Score {
+		return gs.scoreCategory(gs.Categories.Get(a.Category), rollNext)
+	}
+
+	for i := range gs.Jars {
+		if a.Keeps(i) {
+			gs.LockJar(i)
+		} else {
+			gs.UnlockJar(i)
+		}
+	}
+
+	return gs.RollJars()
+}
@@ -0,0 +1,241 @@
+package game
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGameState_LegalActions(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name  string
+		setup func(gs *GameState)
+		want  int
+	}{
+		{
+			name:  "Start of turn can only roll",
+			setup: func(gs *GameState) {},
+			want:  1,
+		},
+		{
+			name: "After first roll",
+			setup: func(gs *GameState) {
+				gs.RollJars()
+			},
+			want: 32 + 9,
+		},
+		{
+			name: "No rolls left and one category used",
+			setup: func(gs *GameState) {
+				gs.RollJars()
+				gs.RollsLeftInTurn = 0
+				gs.Categories.FreeCategory.Used = true
+			},
+			want: 8,
+		},
+		{
+			name: "Game over",
+			setup: func(gs *GameState) {
+				gs.RoundsCompleted = NumCategories
+			},
+			want: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			gs := NewGameWithRoller(DefaultRules(), NewSeededRoller(0))
+			tt.setup(gs)
+
+			actions := gs.LegalActions()
+			if len(actions) != tt.want {
+				t.Errorf("expected %d legal actions, got %d: %v", tt.want, len(actions), actions)
+			}
+
+			for _, action := range actions {
+				if err := gs.CheckAction(action); err != nil {
+					t.Errorf("legal action %s failed check: %v", action, err)
+				}
+			}
+
+			// IsLegal agrees with CheckAction on every action, legal or not
+			all := []Action{{Kind: Reroll, Keep: 1 << 5}, {Kind: Score, Category: NumCategories}}
+			for keep := range uint(1 << 5) {
+				all = append(all, Action{Kind: Reroll, Keep: keep})
+			}
+			for id := range CategoryID(NumCategories) {
+				all = append(all, NewScore(id))
+			}
+			for _, action := range all {
+				if legal := gs.CheckAction(action) == nil; gs.IsLegal(action) != legal {
+					t.Errorf("IsLegal(%s) = %v, want %v", action, !legal, legal)
+				}
+			}
+		})
+	}
+}
+
+func TestGameState_MaxActionsLeft(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name  string
+		setup func(gs *GameState)
+		want  int
+	}{
+		{
+			name:  "Before the first roll",
+			setup: func(gs *GameState) {},
+			want:  28,
+		},
+		{
+			name: "After the first roll",
+			setup: func(gs *GameState) {
+				gs.RollJars()
+			},
+			want: 27,
+		},
+		{
+			name: "Last round with no rolls left",
+			setup: func(gs *GameState) {
+				gs.RollJars()
+				gs.RoundsCompleted = NumCategories - 1
+				gs.RollsLeftInTurn = 0
+			},
+			want: 1,
+		},
+		{
+			name: "Game over",
+			setup: func(gs *GameState) {
+				gs.RoundsCompleted = NumCategories
+			},
+			want: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			gs := NewGameWithRoller(DefaultRules(), NewSeededRoller(0))
+			tt.setup(gs)
+
+			if got := gs.MaxActionsLeft(); got != tt.want {
+				t.Errorf("MaxActionsLeft() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	// the longest game uses every roll before scoring
+	gs := NewGameWithRoller(DefaultRules(), NewSeededRoller(0))
+	gs.RollJars()
+	want := gs.MaxActionsLeft()
+
+	actions := 0
+	for !gs.IsOver() {
+		legal := gs.LegalActions()
+		if err := gs.Apply(legal[0]); err != nil {
+			t.Fatalf("Apply() error = %v", err)
+		}
+		actions++
+	}
+
+	if actions != want {
+		t.Errorf("longest game took %d actions, MaxActionsLeft() = %d", actions, want)
+	}
+}
+
+func TestGameState_Apply(t *testing.T) {
+	t.Parallel()
+	gs := NewGameWithRoller(DefaultRules(), NewFixedRoller(
+		Moonberry, Moonberry, Pest, Pest, Jumbleberry,
+		Moonberry, Sugarberry, Moonberry,
+		Pickleberry, Pickleberry, Pickleberry, Pickleberry, Pickleberry,
+	))
+
+	if err := gs.Apply(NewScore(CatFree)); err == nil {
+		t.Errorf("expected error scoring before rolling but got nil")
+	}
+
+	if err := gs.Apply(NewReroll()); err != nil {
+		t.Fatalf("expected nil error but got %v", err)
+	}
+
+	if err := gs.Apply(NewReroll(0, 1)); err != nil {
+		t.Fatalf("expected nil error but got %v", err)
+	}
+
+	want := []Berry{Moonberry, Moonberry, Moonberry, Sugarberry, Moonberry}
+	if got := gs.GetBerries(); !reflect.DeepEqual(got, want) {
+		t.Errorf("GetBerries() = %v, want %v", got, want)
+	}
+
+	if err := gs.Apply(NewScore(CatMoonberry)); err != nil {
+		t.Fatalf("expected nil error but got %v", err)
+	}
+
+	if gs.Score != 28 || gs.RoundsCompleted != 1 {
+		t.Errorf("expected score 28 after 1 round, got score %d after %d rounds", gs.Score, gs.RoundsCompleted)
+	}
+
+	if err := gs.Apply(NewScore(CatMoonberry)); err == nil {
+		t.Errorf("expected error scoring a used category but got nil")
+	}
+
+	if err := gs.Apply(Action{Kind: Reroll, Keep: 1 << 5}); err == nil {
+		t.Errorf("expected error keeping a jar that doesn't exist but got nil")
+	}
+
+	if err := gs.Apply(NewScore(CategoryID(NumCategories))); err == nil {
+		t.Errorf("expected error scoring an invalid category but got nil")
+	}
+}
+
+func TestAction_String(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		action Action
+		want   string
+	}{
+		{name: "Reroll", action: NewReroll(0, 3), want: "reroll keeping [0 3]"},
+		{name: "Reroll everything", action: NewReroll(), want: "reroll keeping []"},
+		{name: "Score", action: NewScore(CatMixed), want: "score Mixed Basket"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := tt.action.String(); got != tt.want {
+				t.Errorf("Action.String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCategory(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		input   string
+		want    CategoryID
+		wantErr bool
+	}{
+		{name: "Full name", input: "Three of a Kind", want: CatThree},
+		{name: "Case and spaces are ignored", input: "  mixed BASKET ", want: CatMixed},
+		{name: "Prefix", input: "mixed", want: CatMixed},
+		{name: "Short prefix", input: "jumble", want: CatJumbleberry},
+		{name: "Free", input: "free", want: CatFree},
+		{name: "Ambiguous", input: "f", wantErr: true},
+		{name: "Unknown", input: "yahtzee", wantErr: true},
+		{name: "Empty", input: " ", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := ParseCategory(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseCategory() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got != tt.want {
+				t.Errorf("ParseCategory() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,5 +1,10 @@
 package game
 
+import (
+	"fmt"
+	"strings"
+)
+
 const (
 	// different berry types
 	Pest Berry = iota
@@ -38,3 +43,71 @@ func (b Berry) String() string {
 		return "Unknown Berry"
 	}
 }
+
+// Name returns the name of the berry without any color formatting.
+func (b Berry) Name() string {
+	switch b {
+	case Pest:
+		return "Pest"
+	case Jumbleberry:
+		return "Jumbleberry"
+	case Sugarberry:
+		return "Sugarberry"
+	case Pickleberry:
+		return "Pickleberry"
+	case Moonberry:
+		return "Moonberry"
+	default:
+		return "Unknown Berry"
+	}
+}
+
+// berryNames maps every accepted spelling of a berry, in lower case, to the berry.
+var berryNames = map[string]Berry{
+	"j": Jumbleberry, "jbry": Jumbleberry, "jumble": Jumbleberry, "jumbleberry": Jumbleberry,
+	"s": Sugarberry, "sbry": Sugarberry, "sugar": Sugarberry, "sugarberry": Sugarberry,
+	"p": Pickleberry, "pbry": Pickleberry, "pickle": Pickleberry, "pickleberry": Pickleberry,
+	"m": Moonberry, "mbry": Moonberry, "moon": Moonberry, "moonberry": Moonberry,
+	"x": Pest, "-": Pest, "pest": Pest,
+}
+
+// ParseBerry returns the berry named by s, ignoring case.
+// A berry can be written as its name, the abbreviation String prints, or a single letter: J, S, P, M, or X for a pest.
+func ParseBerry(s string) (Berry, error) {
+	b, ok := berryNames[strings.ToLower(strings.TrimSpace(s))]
+	if !ok {
+		return 0, fmt.Errorf("unknown berry %q, use J, S, P, M or X", s)
+	}
+
+	return b, nil
+}
+
+// ParseBerries parses a list of berries separated by spaces or commas, like "J J M P S".
+// Single letters can also be written together, like "JJMPS".
+func ParseBerries(s string) ([]Berry, error) {
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	})
+
+	// splitting a single word of berry letters into one berry per letter
+	if len(fields) == 1 {
+		if _, err := ParseBerry(fields[0]); err != nil {
+			fields = strings.Split(fields[0], "")
+		}
+	}
+
+	var berries []Berry
+	for _, field := range fields {
+		b, err := ParseBerry(field)
+		if err != nil {
+			return nil, err
+		}
+		berries = append(berries, b)
+	}
+
+	if len(berries) == 0 {
+		return nil, fmt.Errorf("no berries provided")
+	}
+
+	return berries, nil
+}
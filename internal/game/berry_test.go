@@ -1,6 +1,9 @@
 package game
 
-import "testing"
+import (
+	"reflect"
+	"testing"
+)
 
 func TestBerry_String(t *testing.T) {
 	t.Parallel()
@@ -51,3 +54,54 @@ func TestBerry_String(t *testing.T) {
 	}
 }
 
+func TestBerry_Name(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		berry    Berry
+		expected string
+	}{
+		{name: "Pest", berry: Pest, expected: "Pest"},
+		{name: "Jumbleberry", berry: Jumbleberry, expected: "Jumbleberry"},
+		{name: "Moonberry", berry: Moonberry, expected: "Moonberry"},
+		{name: "Unknown Berry", berry: Berry(999), expected: "Unknown Berry"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := tt.berry.Name(); got != tt.expected {
+				t.Errorf("Berry.Name() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseBerries(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		input   string
+		want    []Berry
+		wantErr bool
+	}{
+		{name: "Letters", input: "J J M P S", want: []Berry{Jumbleberry, Jumbleberry, Moonberry, Pickleberry, Sugarberry}},
+		{name: "Letters written together", input: "jjmpx", want: []Berry{Jumbleberry, Jumbleberry, Moonberry, Pickleberry, Pest}},
+		{name: "Names and commas", input: "moonberry, Pest,SBRY", want: []Berry{Moonberry, Pest, Sugarberry}},
+		{name: "Single name", input: "pest", want: []Berry{Pest}},
+		{name: "Unknown berry", input: "J J Q", wantErr: true},
+		{name: "Unknown letter written together", input: "JJQ", wantErr: true},
+		{name: "Empty", input: " ", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := ParseBerries(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseBerries() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseBerries() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -5,15 +5,21 @@ import "fmt"
 // A Category is one of the 9 ways you can score in Jumbleberry fields.
 // It should take in a slice of Berries and calculate/store a score.
 // It should also be able to return a score that has already been calculated.
+// PreviewScore returns the score a slice of Berries would get without storing it.
 type Category interface {
 	CalcScore(berries []Berry) (int, error)
+	PreviewScore(berries []Berry) int
 	GetScore() int
+	IsUsed() bool
 }
 
 // A BaseCategory implements the basic functionality for a Category
 type BaseCategory struct {
 	Score int
 	Used  bool
+
+	// rules is the RuleSet the category is scored with. DefaultRules are used if it is nil.
+	rules *RuleSet
 }
 
 // GetScore returns the score for all
@@ -21,6 +27,11 @@ func (b BaseCategory) GetScore() int {
 	return b.Score
 }
 
+// IsUsed returns whether the category has already been scored
+func (b BaseCategory) IsUsed() bool {
+	return b.Used
+}
+
 func (b BaseCategory) String() string {
 	if !b.Used {
 		return "NOT USED, SCORE 0"
@@ -29,124 +40,156 @@ func (b BaseCategory) String() string {
 	return fmt.Sprintf("USED, SCORE %d", b.Score)
 }
 
-// Scoring for the Jumbleberry Section is based on the amount of Jumbleberries rolled.
-// Each Jumbleberry roll is worth 2 points.
-// This means the maximum achievable score for this field is 10 points.
-type JumbleberryCategory struct {
-	BaseCategory
+// ruleSet returns the RuleSet the category is scored with.
+func (b *BaseCategory) ruleSet() *RuleSet {
+	if b.rules == nil {
+		return DefaultRules()
+	}
+
+	return b.rules
 }
 
-// CalcScore returns 2 * the number of Jumbleberries in the provided slice
-func (j *JumbleberryCategory) CalcScore(berries []Berry) (int, error) {
-	if len(berries) != 5 {
-		return -1, fmt.Errorf("len of berries must be 5, got %d", len(berries))
+// checkBerries returns an error if the provided berries can't be scored in the category.
+func (b *BaseCategory) checkBerries(berries []Berry) error {
+	if jars := b.ruleSet().JarCount; len(berries) != jars {
+		return fmt.Errorf("len of berries must be %d, got %d", jars, len(berries))
 	}
 
-	if j.Used {
-		return -1, fmt.Errorf("category has already been scored")
+	if b.Used {
+		return fmt.Errorf("category has already been scored")
 	}
 
-	score := 0
+	return nil
+}
+
+// record marks the category as used with the provided score and returns the score.
+func (b *BaseCategory) record(score int) (int, error) {
+	b.Score = score
+	b.Used = true
+
+	return score, nil
+}
+
+// base returns the BaseCategory itself, so it can be reached through the Category interface.
+func (b *BaseCategory) base() *BaseCategory {
+	return b
+}
+
+// baseOf returns the BaseCategory every category embeds.
+func baseOf(cat Category) *BaseCategory {
+	return cat.(interface{ base() *BaseCategory }).base()
+}
+
+// countBerry returns how many times the target berry appears in berries.
+func countBerry(berries []Berry, target Berry) int {
+	count := 0
 	for _, berry := range berries {
-		if berry == Jumbleberry {
-			score += 2
+		if berry == target {
+			count++
 		}
 	}
 
-	j.Score = score
-	j.Used = true
+	return count
+}
 
-	return score, nil
+// hasNOfAKind returns true if at least n of the berries are the same type.
+// Pests count as a type for this check.
+func hasNOfAKind(berries []Berry, n int) bool {
+	for _, berry := range faceOrder {
+		if countBerry(berries, berry) >= n {
+			return true
+		}
+	}
+
+	return false
 }
 
-// Scoring for the Sugarberry Section is based on the amount of Sugarberries rolled.
-// Each Sugarberry rolled is worth 2 points.
+// Scoring for the Jumbleberry Section is based on the amount of Jumbleberries rolled.
+// Each Jumbleberry roll is worth 2 points under the standard rules.
 // This means the maximum achievable score for this field is 10 points.
-type SugarberryCategory struct {
+type JumbleberryCategory struct {
 	BaseCategory
 }
 
-// CalcScore returns 2 * the number of Sugarberries in the provided slice
-func (s *SugarberryCategory) CalcScore(berries []Berry) (int, error) {
-	if len(berries) != 5 {
-		return -1, fmt.Errorf("len of berries must be 5, got %d", len(berries))
-	}
+// PreviewScore returns the number of Jumbleberries in the provided slice times the Jumbleberry points of the rule set.
+// It doesn't mark the category as used.
+func (j *JumbleberryCategory) PreviewScore(berries []Berry) int {
+	return countBerry(berries, Jumbleberry) * j.ruleSet().Points.Jumbleberry
+}
 
-	if s.Used {
-		return -1, fmt.Errorf("category has already been scored")
+// CalcScore scores the provided berries with PreviewScore and marks the category as used.
+func (j *JumbleberryCategory) CalcScore(berries []Berry) (int, error) {
+	if err := j.checkBerries(berries); err != nil {
+		return -1, err
 	}
 
-	score := 0
-	for _, berry := range berries {
-		if berry == Sugarberry {
-			score += 2
-		}
-	}
+	return j.record(j.PreviewScore(berries))
+}
 
-	s.Score = score
-	s.Used = true
+// Scoring for the Sugarberry Section is based on the amount of Sugarberries rolled.
+// Each Sugarberry rolled is worth 2 points under the standard rules.
+// This means the maximum achievable score for this field is 10 points.
+type SugarberryCategory struct {
+	BaseCategory
+}
 
-	return score, nil
+// PreviewScore returns the number of Sugarberries in the provided slice times the Sugarberry points of the rule set.
+// It doesn't mark the category as used.
+func (s *SugarberryCategory) PreviewScore(berries []Berry) int {
+	return countBerry(berries, Sugarberry) * s.ruleSet().Points.Sugarberry
+}
+
+// CalcScore scores the provided berries with PreviewScore and marks the category as used.
+func (s *SugarberryCategory) CalcScore(berries []Berry) (int, error) {
+	if err := s.checkBerries(berries); err != nil {
+		return -1, err
+	}
+
+	return s.record(s.PreviewScore(berries))
 }
 
 // Scoring for the Pickleberry Section is based on the amount of Pickleberries rolled.
-// Each Pickleberry rolled is worth 4 points.
+// Each Pickleberry rolled is worth 4 points under the standard rules.
 // This means the maximum achievable score for this field is 20 points.
 type PickleberryCategory struct {
 	BaseCategory
 }
 
-// CalcScore returns 4 * the number of Pickleberries in the provided slice
-func (p *PickleberryCategory) CalcScore(berries []Berry) (int, error) {
-	if len(berries) != 5 {
-		return -1, fmt.Errorf("len of berries must be 5, got %d", len(berries))
-	}
-
-	if p.Used {
-		return -1, fmt.Errorf("category has already been scored")
-	}
+// PreviewScore returns the number of Pickleberries in the provided slice times the Pickleberry points of the rule set.
+// It doesn't mark the category as used.
+func (p *PickleberryCategory) PreviewScore(berries []Berry) int {
+	return countBerry(berries, Pickleberry) * p.ruleSet().Points.Pickleberry
+}
 
-	score := 0
-	for _, berry := range berries {
-		if berry == Pickleberry {
-			score += 4
-		}
+// CalcScore scores the provided berries with PreviewScore and marks the category as used.
+func (p *PickleberryCategory) CalcScore(berries []Berry) (int, error) {
+	if err := p.checkBerries(berries); err != nil {
+		return -1, err
 	}
 
-	p.Score = score
-	p.Used = true
-
-	return score, nil
+	return p.record(p.PreviewScore(berries))
 }
 
 // Scoring for the Moonberry Section is based on the amount of Moonberries rolled.
-// Each Moonberry rolled is worth 7 points.
+// Each Moonberry rolled is worth 7 points under the standard rules.
 // This means the maximum achievable score for this field is 35 points.
 type MoonberryCategory struct {
 	BaseCategory
 }
 
-// CalcScore returns 7 * the number of Moonberries in the provided slice
-func (m *MoonberryCategory) CalcScore(berries []Berry) (int, error) {
-	if len(berries) != 5 {
-		return -1, fmt.Errorf("len of berries must be 5, got %d", len(berries))
-	}
-
-	if m.Used {
-		return -1, fmt.Errorf("category has already been scored")
-	}
+// PreviewScore returns the number of Moonberries in the provided slice times the Moonberry points of the rule set.
+// It doesn't mark the category as used.
+func (m *MoonberryCategory) PreviewScore(berries []Berry) int {
+	return countBerry(berries, Moonberry) * m.ruleSet().Points.Moonberry
+}
 
-	score := 0
-	for _, berry := range berries {
-		if berry == Moonberry {
-			score += 7
-		}
+// CalcScore scores the provided berries with PreviewScore and marks the category as used.
+func (m *MoonberryCategory) CalcScore(berries []Berry) (int, error) {
+	if err := m.checkBerries(berries); err != nil {
+		return -1, err
 	}
 
-	m.Score = score
-	m.Used = true
-
-	return score, nil
+	return m.record(m.PreviewScore(berries))
 }
 
 // In order to be able to score in this section you need to have at least 3 of one type of berry, or 3 pests.
@@ -157,48 +200,24 @@ type ThreeCategory struct {
 	BaseCategory
 }
 
-// CalcScore will return the combined score of all 5 berries/pests, as long as there are at least three of one type.
+// PreviewScore will return the combined score of all 5 berries/pests, as long as there are at least three of one type.
 // If there are not three of one type, it will return 0.
-func (t *ThreeCategory) CalcScore(berries []Berry) (int, error) {
-	if len(berries) != 5 {
-		return -1, fmt.Errorf("len of berries must be 5, got %d", len(berries))
-	}
-
-	if t.Used {
-		return -1, fmt.Errorf("category has already been scored")
-	}
-
-	jberryCount, sberryCount, pberryCount, mberryCount, pestCount := 0, 0, 0, 0, 0
-	score := 0
-
-	for _, berry := range berries {
-		switch berry {
-		case Jumbleberry:
-			score += 2
-			jberryCount += 1
-		case Sugarberry:
-			score += 2
-			sberryCount += 1
-		case Pickleberry:
-			score += 4
-			pberryCount += 1
-		case Moonberry:
-			score += 7
-			mberryCount += 1
-		case Pest:
-			pestCount += 1
-		}
+// It doesn't mark the category as used.
+func (t *ThreeCategory) PreviewScore(berries []Berry) int {
+	if !hasNOfAKind(berries, 3) {
+		return 0
 	}
 
-	t.Used = true
-	t.Score  = 0
+	return t.ruleSet().SumPoints(berries)
+}
 
-	if jberryCount >= 3 || sberryCount >= 3 || pberryCount >= 3 || mberryCount >= 3 || pestCount >= 3 {
-		t.Score = score
-		return score, nil
+// CalcScore scores the provided berries with PreviewScore and marks the category as used.
+func (t *ThreeCategory) CalcScore(berries []Berry) (int, error) {
+	if err := t.checkBerries(berries); err != nil {
+		return -1, err
 	}
 
-	return 0, nil
+	return t.record(t.PreviewScore(berries))
 }
 
 // In order to be able to score in this section you need to have at least 4 of one type of berry, or 4 pests.
@@ -209,147 +228,88 @@ type FourCategory struct {
 	BaseCategory
 }
 
-// CalcScore will return the combined score of all 5 berries/pests, as long as there are at least four of one type.
+// PreviewScore will return the combined score of all 5 berries/pests, as long as there are at least four of one type.
 // If there are not four of one type, it will return 0.
-func (f *FourCategory) CalcScore(berries []Berry) (int, error) {
-	if len(berries) != 5 {
-		return -1, fmt.Errorf("len of berries must be 5, got %d", len(berries))
+// It doesn't mark the category as used.
+func (f *FourCategory) PreviewScore(berries []Berry) int {
+	if !hasNOfAKind(berries, 4) {
+		return 0
 	}
 
-	if f.Used {
-		return -1, fmt.Errorf("category has already been scored")
-	}
-
-	jberryCount, sberryCount, pberryCount, mberryCount, pestCount := 0, 0, 0, 0, 0
-	score := 0
-
-	for _, berry := range berries {
-		switch berry {
-		case Jumbleberry:
-			score += 2
-			jberryCount += 1
-		case Sugarberry:
-			score += 2
-			sberryCount += 1
-		case Pickleberry:
-			score += 4
-			pberryCount += 1
-		case Moonberry:
-			score += 7
-			mberryCount += 1
-		case Pest:
-			pestCount += 1
-		}
-	}
-
-	f.Used = true
-	f.Score = 0
+	return f.ruleSet().SumPoints(berries)
+}
 
-	if jberryCount >= 4 || sberryCount >= 4 || pberryCount >= 4 || mberryCount >= 4 || pestCount >= 4 {
-		f.Score = score
-		return score, nil
+// CalcScore scores the provided berries with PreviewScore and marks the category as used.
+func (f *FourCategory) CalcScore(berries []Berry) (int, error) {
+	if err := f.checkBerries(berries); err != nil {
+		return -1, err
 	}
 
-	return 0, nil
+	return f.record(f.PreviewScore(berries))
 }
 
 // In order to be able to score in this section you need to have 5 berries of the same type.
 // This field is the hardest one to score in and in many games is recorded as a zero.
 // The easiest way to score this field is to try to get five Jumbleberries or five Sugarberries.
-// This will give you a score of 10 for this field.
+// This will give you a score of 10 for this field under the standard rules.
 type FiveCategory struct {
 	BaseCategory
 }
 
-// CalcScore will return 5 * berry value if there are five of the same type, or 0 if there aren't 5 of a kind.
-func (f *FiveCategory) CalcScore(berries []Berry) (int, error) {
-	if len(berries) != 5 {
-		return -1, fmt.Errorf("len of berries must be 5, got %d", len(berries))
+// PreviewScore will return the Five of a Kind payout for the berry if there are five of the same type,
+// or 0 if there aren't 5 of a kind.
+// It doesn't mark the category as used.
+func (f *FiveCategory) PreviewScore(berries []Berry) int {
+	if len(berries) == 0 {
+		return 0
 	}
 
-	if f.Used {
-		return -1, fmt.Errorf("category has already been scored")
-	}
-
-	f.Used = true
-
 	// checking if all 5 berries are the same type
 	// if they aren't return 0
 	berryType := berries[0]
-	for _, berry := range berries {
-		if berry != berryType {
-			f.Score = 0
-			return 0, nil
-		}
+	if countBerry(berries, berryType) != len(berries) {
+		return 0
 	}
 
-	// if all 5 are the same type, determine how many points to score
-	switch berryType {
-	case Jumbleberry:
-		f.Score = 10
-		return 10, nil
-	case Sugarberry:
-		f.Score = 10
-		return 10, nil
-	case Pickleberry:
-		f.Score = 20
-		return 20, nil
-	case Moonberry:
-		f.Score = 35
-		return 35, nil
-	default:
-		f.Score = 0
-		return 0, nil
+	// if all 5 are the same type, the payout depends on the type
+	return f.ruleSet().FiveOfAKind.Of(berryType)
+}
+
+// CalcScore scores the provided berries with PreviewScore and marks the category as used.
+func (f *FiveCategory) CalcScore(berries []Berry) (int, error) {
+	if err := f.checkBerries(berries); err != nil {
+		return -1, err
 	}
+
+	return f.record(f.PreviewScore(berries))
 }
 
 // In order to be able to score in this section you must have one Jumbleberry, one Sugarberry, one Pickleberry, and one Moonberry.
-// Scoring is based on the total of all 5 dice for this section so the maximum score for this section is 22 (if you have an extra Moonberry).
+// Scoring is based on the total of all 5 dice for this section so the maximum score for this section is 22 under the standard rules (if you have an extra Moonberry).
 type MixedCategory struct {
 	BaseCategory
 }
 
-// CalcScore returns the sum of the score of all berries if all four types of berries are present.
+// PreviewScore returns the sum of the score of all berries if all four types of berries are present.
 // If not all four types are present, it returns 0.
-func (m *MixedCategory) CalcScore(berries []Berry) (int, error) {
-	if len(berries) != 5 {
-		return -1, fmt.Errorf("len of berries must be 5, got %d", len(berries))
-	}
-
-	if m.Used {
-		return -1, fmt.Errorf("category has already been scored")
-	}
-
-	foundJB, foundSB, foundPB, foundMB := false, false, false, false
-	score := 0
-
-	for _, berry := range berries {
-		switch berry {
-		case Jumbleberry:
-			foundJB = true
-			score += 2
-		case Sugarberry:
-			foundSB = true
-			score += 2
-		case Pickleberry:
-			foundPB = true
-			score += 4
-		case Moonberry:
-			foundMB = true
-			score += 7
+// It doesn't mark the category as used.
+func (m *MixedCategory) PreviewScore(berries []Berry) int {
+	for _, berry := range []Berry{Jumbleberry, Sugarberry, Pickleberry, Moonberry} {
+		if countBerry(berries, berry) == 0 {
+			return 0
 		}
 	}
 
-	m.Used = true
+	return m.ruleSet().SumPoints(berries)
+}
 
-	if !(foundJB && foundSB && foundPB && foundMB) {
-		m.Score = 0
-		return 0, nil
+// CalcScore scores the provided berries with PreviewScore and marks the category as used.
+func (m *MixedCategory) CalcScore(berries []Berry) (int, error) {
+	if err := m.checkBerries(berries); err != nil {
+		return -1, err
 	}
 
-	m.Score = score
-
-	return score, nil
+	return m.record(m.PreviewScore(berries))
 }
 
 // This category adds up the score of all provided berries, regardless of what berries are present.
@@ -357,32 +317,17 @@ type FreeCategory struct {
 	BaseCategory
 }
 
-// CalcScore returns the score of all of the berries added together, regardless fo the type
-func (f *FreeCategory) CalcScore(berries []Berry) (int, error) {
-	if len(berries) != 5 {
-		return -1, fmt.Errorf("len of berries must be 5, got %d", len(berries))
-	}
-
-	if f.Used {
-		return -1, fmt.Errorf("category has already been scored")
-	}
+// PreviewScore returns the score of all of the berries added together, regardless fo the type
+// It doesn't mark the category as used.
+func (f *FreeCategory) PreviewScore(berries []Berry) int {
+	return f.ruleSet().SumPoints(berries)
+}
 
-	score := 0
-	for _, berry := range berries {
-		switch berry {
-		case Jumbleberry:
-			score += 2
-		case Sugarberry:
-			score += 2
-		case Pickleberry:
-			score += 4
-		case Moonberry:
-			score += 7
-		}
+// CalcScore scores the provided berries with PreviewScore and marks the category as used.
+func (f *FreeCategory) CalcScore(berries []Berry) (int, error) {
+	if err := f.checkBerries(berries); err != nil {
+		return -1, err
 	}
 
-	f.Used = true
-	f.Score = score
-
-	return score, nil
+	return f.record(f.PreviewScore(berries))
 }
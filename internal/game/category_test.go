@@ -955,3 +955,38 @@ func TestFreeCategory_CalcScore(t *testing.T) {
 		})
 	}
 }
+
+func TestCategory_PreviewScore(t *testing.T) {
+	t.Parallel()
+	berries := []Berry{Jumbleberry, Sugarberry, Pickleberry, Moonberry, Moonberry}
+	tests := []struct {
+		name     string
+		category Category
+		want     int
+	}{
+		{name: "Jumbleberry", category: &JumbleberryCategory{}, want: 2},
+		{name: "Sugarberry", category: &SugarberryCategory{}, want: 2},
+		{name: "Pickleberry", category: &PickleberryCategory{}, want: 4},
+		{name: "Moonberry", category: &MoonberryCategory{}, want: 14},
+		{name: "Three of a Kind", category: &ThreeCategory{}, want: 0},
+		{name: "Four of a Kind", category: &FourCategory{}, want: 0},
+		{name: "Five of a Kind", category: &FiveCategory{}, want: 0},
+		{name: "Mixed", category: &MixedCategory{}, want: 22},
+		{name: "Free", category: &FreeCategory{}, want: 22},
+		{name: "Used category", category: &FreeCategory{BaseCategory{Used: true, Score: 3}}, want: 22},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			used, score := tt.category.IsUsed(), tt.category.GetScore()
+
+			if got := tt.category.PreviewScore(berries); got != tt.want {
+				t.Errorf("PreviewScore() = %v, want %v", got, tt.want)
+			}
+
+			if tt.category.IsUsed() != used || tt.category.GetScore() != score {
+				t.Errorf("PreviewScore() changed the category")
+			}
+		})
+	}
+}
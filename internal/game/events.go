@@ -0,0 +1,174 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// An EventKind is the type of change an Event records.
+type EventKind string
+
+const (
+	// EventRoll records a roll of the jars and the berry in every jar afterwards.
+	EventRoll EventKind = "roll"
+
+	// EventLock and EventUnlock record a jar being locked or unlocked.
+	EventLock   EventKind = "lock"
+	EventUnlock EventKind = "unlock"
+
+	// EventScore records a category being scored and the points it got.
+	EventScore EventKind = "score"
+
+	// EventTurn records the start of a new turn.
+	EventTurn EventKind = "turn"
+)
+
+// An Event is a single change to a GameState.
+type Event struct {
+	Kind EventKind `json:"kind"`
+
+	// Round is the round the game is in once the event has happened, starting from 1.
+	Round int `json:"round"`
+
+	// Berries holds the berry in every jar after a roll.
+	Berries []Berry `json:"berries,omitempty"`
+
+	// Jar is the jar that was locked or unlocked.
+	Jar int `json:"jar"`
+
+	// Category and Points are the category that was scored and the points it got.
+	Category CategoryID `json:"category"`
+	Points   int        `json:"points"`
+}
+
+// MarshalJSON encodes the event with only the fields its kind uses.
+func (e Event) MarshalJSON() ([]byte, error) {
+	type eventJSON struct {
+		Kind     EventKind   `json:"kind"`
+		Round    int         `json:"round"`
+		Berries  []Berry     `json:"berries,omitempty"`
+		Jar      *int        `json:"jar,omitempty"`
+		Category *CategoryID `json:"category,omitempty"`
+		Points   *int        `json:"points,omitempty"`
+	}
+
+	out := eventJSON{Kind: e.Kind, Round: e.Round, Berries: e.Berries}
+	switch e.Kind {
+	case EventLock, EventUnlock:
+		out.Jar = &e.Jar
+	case EventScore:
+		out.Category, out.Points = &e.Category, &e.Points
+	}
+
+	return json.Marshal(out)
+}
+
+// An EventLog is every change made to a game since it started, in order.
+type EventLog struct {
+	Rules  *RuleSet `json:"rules,omitempty"`
+	Events []Event  `json:"events"`
+}
+
+// EnableLog starts recording every change to the game in gs.Log.
+// The log must start with the game, so it returns an error once the jars have been rolled.
+func (gs *GameState) EnableLog() error {
+	if gs.RoundsCompleted > 0 || gs.RollsLeftInTurn != gs.ruleSet().RollsPerTurn {
+		return fmt.Errorf("the event log must be enabled before the first roll")
+	}
+
+	gs.Log = &EventLog{Rules: gs.Rules}
+	return nil
+}
+
+// record adds an event to the log of the game, if it has one.
+func (gs *GameState) record(e Event) {
+	if gs.Log == nil {
+		return
+	}
+
+	e.Round = gs.RoundsCompleted + 1
+	gs.Log.Events = append(gs.Log.Events, e)
+}
+
+// Replay returns the game state after the first n events of the log.
+// Every event is checked against the rules, so a log that couldn't have come from a real game returns an error.
+// The returned game keeps logging, starting from the replayed events.
+func Replay(log *EventLog, n int) (*GameState, error) {
+	if n < 0 || n > len(log.Events) {
+		return nil, fmt.Errorf("can't replay %d events of a log with %d", n, len(log.Events))
+	}
+
+	gs := NewGame(log.Rules)
+	for i, e := range log.Events[:n] {
+		if err := gs.replayEvent(e); err != nil {
+			return nil, fmt.Errorf("event %d (%s): %w", i, e.Kind, err)
+		}
+	}
+
+	gs.Log = &EventLog{Rules: log.Rules, Events: append([]Event{}, log.Events[:n]...)}
+	return gs, nil
+}
+
+// replayEvent applies an event to the game state without recording it.
+func (gs *GameState) replayEvent(e Event) error {
+	rules := gs.ruleSet()
+
+	switch e.Kind {
+	case EventRoll:
+		if gs.RollsLeftInTurn < 1 {
+			return fmt.Errorf("no rolls left")
+		}
+
+		if len(e.Berries) != len(gs.Jars) {
+			return fmt.Errorf("roll has %d berries, expected %d", len(e.Berries), len(gs.Jars))
+		}
+
+		for i, jar := range gs.Jars {
+			if jar.Rolled && jar.Locked && jar.Berry != e.Berries[i] {
+				return fmt.Errorf("locked jar %d changed from %s to %s", i, jar.Berry.Name(), e.Berries[i].Name())
+			}
+
+			jar.Berry = e.Berries[i]
+			jar.Rolled = true
+		}
+
+		gs.RollsLeftInTurn--
+	case EventLock, EventUnlock:
+		if e.Jar < 0 || e.Jar >= len(gs.Jars) {
+			return fmt.Errorf("invalid jar %d", e.Jar)
+		}
+
+		gs.Jars[e.Jar].Locked = e.Kind == EventLock
+	case EventScore:
+		cat := gs.Categories.Get(e.Category)
+		if cat == nil {
+			return fmt.Errorf("invalid category %d", e.Category)
+		}
+
+		if cat.IsUsed() {
+			return fmt.Errorf("category %s has already been scored", e.Category)
+		}
+
+		if gs.RollsLeftInTurn >= rules.RollsPerTurn {
+			return fmt.Errorf("must have rolled once to score category")
+		}
+
+		if points := cat.PreviewScore(gs.GetBerries()); points != e.Points {
+			return fmt.Errorf("%s scores %d points, but the log has %d", e.Category, points, e.Points)
+		}
+
+		baseOf(cat).record(e.Points)
+		gs.Score += e.Points
+	case EventTurn:
+		for _, jar := range gs.Jars {
+			jar.Reset()
+		}
+
+		gs.RollsLeftInTurn = rules.RollsPerTurn
+		gs.RoundsCompleted++
+	default:
+		return fmt.Errorf("unknown event kind %q", e.Kind)
+	}
+
+	return nil
+}
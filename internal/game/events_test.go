@@ -0,0 +1,171 @@
+package game
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// loggedGame plays a game with logging enabled and returns it,
+// along with the game state encoded as JSON after each action and the number of events at that point.
+func loggedGame(t *testing.T) (*GameState, []string, []int) {
+	t.Helper()
+	gs := NewGameWithRoller(nil, NewSeededRoller(4))
+	if err := gs.EnableLog(); err != nil {
+		t.Fatalf("EnableLog() error = %v", err)
+	}
+	gs.RollJars()
+
+	var snapshots []string
+	var counts []int
+	snapshot := func() {
+		data, err := json.Marshal(gs)
+		if err != nil {
+			t.Fatalf("Marshal() error = %v", err)
+		}
+		snapshots = append(snapshots, string(data))
+		counts = append(counts, len(gs.Log.Events))
+	}
+	snapshot()
+
+	for id := range CategoryID(NumCategories) {
+		for _, action := range []Action{NewReroll(0, 1), NewReroll(1, 4), NewScore(id)} {
+			if err := gs.Apply(action); err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+			snapshot()
+		}
+	}
+
+	return gs, snapshots, counts
+}
+
+func TestReplay(t *testing.T) {
+	t.Parallel()
+	gs, snapshots, counts := loggedGame(t)
+
+	for i, n := range counts {
+		replayed, err := Replay(gs.Log, n)
+		if err != nil {
+			t.Fatalf("Replay() of %d events error = %v", n, err)
+		}
+
+		data, err := json.Marshal(replayed)
+		if err != nil {
+			t.Fatalf("Marshal() error = %v", err)
+		}
+
+		if string(data) != snapshots[i] {
+			t.Fatalf("Replay() of %d events = %s, want %s", n, data, snapshots[i])
+		}
+	}
+
+	final, err := Replay(gs.Log, len(gs.Log.Events))
+	if err != nil {
+		t.Fatalf("Replay() error = %v", err)
+	}
+
+	if !final.IsOver() || final.Score != gs.Score || !reflect.DeepEqual(final.Log, gs.Log) {
+		t.Errorf("Replay() of the whole log scored %d, want %d", final.Score, gs.Score)
+	}
+}
+
+func TestGameState_EnableLog(t *testing.T) {
+	t.Parallel()
+	gs := NewGameWithRoller(nil, NewFixedRoller(Moonberry, Moonberry, Pest, Pest, Sugarberry, Moonberry, Moonberry, Moonberry))
+	if err := gs.EnableLog(); err != nil {
+		t.Fatalf("EnableLog() error = %v", err)
+	}
+	gs.RollJars()
+
+	if err := gs.Apply(NewReroll(0, 1)); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+
+	want := []Event{
+		{Kind: EventRoll, Round: 1, Berries: []Berry{Moonberry, Moonberry, Pest, Pest, Sugarberry}},
+		{Kind: EventLock, Round: 1, Jar: 0},
+		{Kind: EventLock, Round: 1, Jar: 1},
+		{Kind: EventRoll, Round: 1, Berries: []Berry{Moonberry, Moonberry, Moonberry, Moonberry, Moonberry}},
+	}
+	if !reflect.DeepEqual(gs.Log.Events, want) {
+		t.Errorf("Log.Events = %+v, want %+v", gs.Log.Events, want)
+	}
+
+	if err := gs.EnableLog(); err == nil {
+		t.Errorf("EnableLog() expected an error after the jars were rolled")
+	}
+}
+
+func TestEventLog_JSON(t *testing.T) {
+	t.Parallel()
+	gs, _, _ := loggedGame(t)
+
+	data, err := json.Marshal(gs.Log)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	for _, want := range []string{`{"kind":"score","round":1,"category":"jumbleberry","points":`, `{"kind":"lock","round":1,"jar":0}`, `{"kind":"turn","round":2}`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("Marshal() = %s, doesn't contain %s", data, want)
+		}
+	}
+
+	var log EventLog
+	if err := json.Unmarshal(data, &log); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(log.Events, gs.Log.Events) {
+		t.Errorf("events don't round trip through JSON")
+	}
+}
+
+func TestReplay_Invalid(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		modify func(events []Event)
+	}{
+		{name: "Wrong points", modify: func(events []Event) {
+			for i := range events {
+				if events[i].Kind == EventScore {
+					events[i].Points += 3
+					return
+				}
+			}
+		}},
+		{name: "Locked jar changes", modify: func(events []Event) {
+			// the first reroll keeps jars 0 and 1
+			for i := 1; i < len(events); i++ {
+				if events[i].Kind == EventRoll {
+					events[i].Berries = append([]Berry{}, events[i].Berries...)
+					events[i].Berries[0] = (events[i].Berries[0] + 1) % 5
+					return
+				}
+			}
+		}},
+		{name: "Unknown kind", modify: func(events []Event) { events[0].Kind = "shuffle" }},
+		{name: "Roll without rolls left", modify: func(events []Event) { events[2] = events[0] }},
+		{name: "Invalid jar", modify: func(events []Event) { events[1].Jar = 7 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			gs, _, _ := loggedGame(t)
+			events := append([]Event{}, gs.Log.Events...)
+			tt.modify(events)
+
+			if _, err := Replay(&EventLog{Events: events}, len(events)); err == nil {
+				t.Errorf("Replay() expected an error but got nil")
+			}
+		})
+	}
+
+	gs, _, _ := loggedGame(t)
+	if _, err := Replay(gs.Log, len(gs.Log.Events)+1); err == nil {
+		t.Errorf("Replay() expected an error for too many events")
+	}
+}
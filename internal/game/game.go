@@ -1,15 +1,29 @@
 package game
 
-import "fmt"
+import (
+	"fmt"
+	"math/rand"
+)
 
 // A GameState represents the state for a single game.
 // It is comprised of the score, jars, rounds left, rolls left and categories.
 type GameState struct {
-	Categories      GameCategories
-	Jars            []*Jar
-	RollsLeftInTurn int
-	RoundsCompleted int
-	Score           int
+	Categories      GameCategories `json:"scorecard"`
+	Jars            []*Jar         `json:"jars"`
+	RollsLeftInTurn int            `json:"rollsLeft"`
+	RoundsCompleted int            `json:"roundsCompleted"`
+	Score           int            `json:"score"`
+
+	// Rules is the RuleSet the game is played with. DefaultRules are used if it is nil.
+	Rules *RuleSet `json:"rules,omitempty"`
+
+	// Roller is the source of all dice rolls in the game.
+	// If it is nil, a randomly seeded Roller is created the first time the jars are rolled.
+	// It isn't saved to JSON.
+	Roller Roller `json:"-"`
+
+	// Log records every change to the game once EnableLog has been called. It isn't saved to JSON.
+	Log *EventLog `json:"-"`
 }
 
 // A GameCategories object represents the 9 different scoring categories.
@@ -25,25 +39,82 @@ type GameCategories struct {
 	FreeCategory        *FreeCategory
 }
 
-// NewGame returns a *GameState in the starting state
-func NewGame() *GameState {
+// All returns the 9 categories in scorecard order.
+func (gc GameCategories) All() []Category {
+	return []Category{
+		gc.JumbleberryCategory,
+		gc.SugarberryCategory,
+		gc.PickleberryCategory,
+		gc.MoonberryCategory,
+		gc.ThreeCategory,
+		gc.FourCategory,
+		gc.FiveCategory,
+		gc.MixedCategory,
+		gc.FreeCategory,
+	}
+}
+
+// NewGame returns a *GameState in the starting state for the provided rules, using a randomly seeded Roller.
+// If rules is nil, DefaultRules are used.
+func NewGame(rules *RuleSet) *GameState {
+	return NewGameWithRoller(rules, nil)
+}
+
+// NewGameWithRoller returns a *GameState in the starting state for the provided rules that draws all of its rolls from r.
+// If rules is nil, DefaultRules are used.
+func NewGameWithRoller(rules *RuleSet, r Roller) *GameState {
+	if rules == nil {
+		rules = DefaultRules()
+	}
+
+	jars := make([]*Jar, rules.JarCount)
+	for i := range jars {
+		jars[i] = &Jar{}
+	}
+
 	return &GameState{
-		Jars:            []*Jar{{}, {}, {}, {}, {}},
-		RollsLeftInTurn: 3,
-		Categories: GameCategories{
-			JumbleberryCategory: &JumbleberryCategory{},
-			SugarberryCategory:  &SugarberryCategory{},
-			PickleberryCategory: &PickleberryCategory{},
-			MoonberryCategory:   &MoonberryCategory{},
-			ThreeCategory:       &ThreeCategory{},
-			FourCategory:        &FourCategory{},
-			FiveCategory:        &FiveCategory{},
-			MixedCategory:       &MixedCategory{},
-			FreeCategory:        &FreeCategory{},
-		},
+		Rules:           rules,
+		Roller:          r,
+		Jars:            jars,
+		RollsLeftInTurn: rules.RollsPerTurn,
+		Categories:      newGameCategories(rules),
 	}
 }
 
+// newGameCategories returns a set of unused categories that are scored with the provided rules.
+func newGameCategories(rules *RuleSet) GameCategories {
+	return GameCategories{
+		JumbleberryCategory: &JumbleberryCategory{BaseCategory{rules: rules}},
+		SugarberryCategory:  &SugarberryCategory{BaseCategory{rules: rules}},
+		PickleberryCategory: &PickleberryCategory{BaseCategory{rules: rules}},
+		MoonberryCategory:   &MoonberryCategory{BaseCategory{rules: rules}},
+		ThreeCategory:       &ThreeCategory{BaseCategory{rules: rules}},
+		FourCategory:        &FourCategory{BaseCategory{rules: rules}},
+		FiveCategory:        &FiveCategory{BaseCategory{rules: rules}},
+		MixedCategory:       &MixedCategory{BaseCategory{rules: rules}},
+		FreeCategory:        &FreeCategory{BaseCategory{rules: rules}},
+	}
+}
+
+// Clone returns a deep copy of the game state that shares its rules. The copy has no Roller or Log,
+// so rolling its jars draws from a new randomly seeded Roller and leaves the dice of the original alone.
+func (gs *GameState) Clone() *GameState {
+	clone := *gs
+	clone.Roller, clone.Log = nil, nil
+
+	clone.Jars = make([]*Jar, len(gs.Jars))
+	for i, jar := range gs.Jars {
+		clone.Jars[i] = &Jar{Berry: jar.Berry, Locked: jar.Locked, Rolled: jar.Rolled}
+	}
+
+	clone.Categories = newGameCategories(gs.Rules)
+	for i, cat := range gs.Categories.All() {
+		*baseOf(clone.Categories.All()[i]) = *baseOf(cat)
+	}
+
+	return &clone
+}
+
 // RollJars rolls all jars in a game state.
 // If a Jar is locked, then it won't be rolled.
 func (gs *GameState) RollJars() error {
@@ -51,14 +122,35 @@ func (gs *GameState) RollJars() error {
 		return fmt.Errorf("no rolls left")
 	}
 
+	roller := gs.dice()
 	for _, jar := range gs.Jars {
-		jar.Roll()
+		jar.Roll(roller)
 	}
 
 	gs.RollsLeftInTurn -= 1
+	gs.record(Event{Kind: EventRoll, Berries: gs.GetBerries()})
+
 	return nil
 }
 
+// ruleSet returns the RuleSet the game is played with.
+func (gs *GameState) ruleSet() *RuleSet {
+	if gs.Rules == nil {
+		return DefaultRules()
+	}
+
+	return gs.Rules
+}
+
+// dice returns the Roller for the game, creating a randomly seeded one if none has been set.
+func (gs *GameState) dice() Roller {
+	if gs.Roller == nil {
+		gs.Roller = gs.ruleSet().NewRoller(rand.Int63())
+	}
+
+	return gs.Roller
+}
+
 func (gs *GameState) GetBerries() []Berry {
 	var berries []Berry
 
@@ -74,19 +166,28 @@ func (gs *GameState) NewTurn() {
 		jar.Reset()
 	}
 
-	gs.RollsLeftInTurn = 3
+	gs.RollsLeftInTurn = gs.ruleSet().RollsPerTurn
 	gs.RoundsCompleted += 1
+	gs.record(Event{Kind: EventTurn})
 }
 
 // LockJar will lock a given jar in a GameState.
 // If a Jar is already locked, it will stay locked
 func (gs *GameState) LockJar(n int) {
+	if !gs.Jars[n].Locked {
+		gs.record(Event{Kind: EventLock, Jar: n})
+	}
+
 	gs.Jars[n].Lock()
 }
 
 // UnlockJar will unlock a given jar in a GameState.
 // If a Jar is already unlocked, it will stay unlocked
 func (gs *GameState) UnlockJar(n int) {
+	if gs.Jars[n].Locked {
+		gs.record(Event{Kind: EventUnlock, Jar: n})
+	}
+
 	gs.Jars[n].Unlock()
 }
 
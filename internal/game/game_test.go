@@ -7,33 +7,48 @@ import (
 
 func TestNewGame(t *testing.T) {
 	t.Parallel()
+	rules := DefaultRules()
+	houseRules := &RuleSet{FaceWeights: rules.FaceWeights, RollsPerTurn: 4, JarCount: 3}
 	tests := []struct {
-		name string
-		want *GameState
+		name  string
+		rules *RuleSet
+		want  *GameState
 	}{
 		{
-			name: "New Game",
+			name:  "New Game",
+			rules: rules,
 			want: &GameState{
+				Rules:           rules,
 				Jars:            []*Jar{{}, {}, {}, {}, {}},
 				RollsLeftInTurn: 3,
 				Categories: GameCategories{
-					JumbleberryCategory: &JumbleberryCategory{},
-					SugarberryCategory:  &SugarberryCategory{},
-					PickleberryCategory: &PickleberryCategory{},
-					MoonberryCategory:   &MoonberryCategory{},
-					ThreeCategory:       &ThreeCategory{},
-					FourCategory:        &FourCategory{},
-					FiveCategory:        &FiveCategory{},
-					MixedCategory:       &MixedCategory{},
-					FreeCategory:        &FreeCategory{},
+					JumbleberryCategory: &JumbleberryCategory{BaseCategory{rules: rules}},
+					SugarberryCategory:  &SugarberryCategory{BaseCategory{rules: rules}},
+					PickleberryCategory: &PickleberryCategory{BaseCategory{rules: rules}},
+					MoonberryCategory:   &MoonberryCategory{BaseCategory{rules: rules}},
+					ThreeCategory:       &ThreeCategory{BaseCategory{rules: rules}},
+					FourCategory:        &FourCategory{BaseCategory{rules: rules}},
+					FiveCategory:        &FiveCategory{BaseCategory{rules: rules}},
+					MixedCategory:       &MixedCategory{BaseCategory{rules: rules}},
+					FreeCategory:        &FreeCategory{BaseCategory{rules: rules}},
 				},
 			},
 		},
+		{
+			name:  "House Rules",
+			rules: houseRules,
+			want: &GameState{
+				Rules:           houseRules,
+				Jars:            []*Jar{{}, {}, {}},
+				RollsLeftInTurn: 4,
+				Categories:      newGameCategories(houseRules),
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
-			if got := NewGame(); !reflect.DeepEqual(got, tt.want) {
+			if got := NewGame(tt.rules); !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("NewGame() = %v, want %v", got, tt.want)
 			}
 		})
@@ -42,7 +57,7 @@ func TestNewGame(t *testing.T) {
 
 func TestGameState_RollJars_Success(t *testing.T) {
 	t.Parallel()
-	gs := NewGame()
+	gs := NewGame(DefaultRules())
 
 	err := gs.RollJars()
 
@@ -63,7 +78,7 @@ func TestGameState_RollJars_Success(t *testing.T) {
 
 func TestGameState_RollJars_NoRollLeft(t *testing.T) {
 	t.Parallel()
-	gs := NewGame()
+	gs := NewGame(DefaultRules())
 
 	gs.RollsLeftInTurn = 0
 
@@ -76,7 +91,7 @@ func TestGameState_RollJars_NoRollLeft(t *testing.T) {
 
 func TestGameState_LockJar(t *testing.T) {
 	t.Parallel()
-	gs := NewGame()
+	gs := NewGame(DefaultRules())
 
 	gs.LockJar(0)
 
@@ -86,7 +101,7 @@ func TestGameState_LockJar(t *testing.T) {
 }
 
 func TestGameState_String(t *testing.T) {
-	gs := NewGame()
+	gs := NewGame(DefaultRules())
 
 	got := gs.String()
 
@@ -95,3 +110,60 @@ func TestGameState_String(t *testing.T) {
 		t.Errorf("incorrect string, got: %s", got)
 	}
 }
+
+func TestNewGameWithRoller(t *testing.T) {
+	t.Parallel()
+	gs := NewGameWithRoller(DefaultRules(), NewFixedRoller(Jumbleberry, Sugarberry, Pickleberry, Moonberry, Pest, Moonberry))
+
+	if err := gs.RollJars(); err != nil {
+		t.Fatalf("expected nil error but got %v", err)
+	}
+
+	want := []Berry{Jumbleberry, Sugarberry, Pickleberry, Moonberry, Pest}
+	if got := gs.GetBerries(); !reflect.DeepEqual(got, want) {
+		t.Errorf("GetBerries() = %v, want %v", got, want)
+	}
+
+	gs.LockJar(0)
+	gs.LockJar(1)
+	gs.LockJar(2)
+	gs.LockJar(3)
+
+	if err := gs.RollJars(); err != nil {
+		t.Fatalf("expected nil error but got %v", err)
+	}
+
+	want = []Berry{Jumbleberry, Sugarberry, Pickleberry, Moonberry, Moonberry}
+	if got := gs.GetBerries(); !reflect.DeepEqual(got, want) {
+		t.Errorf("GetBerries() = %v, want %v", got, want)
+	}
+}
+
+func TestGameState_ScoreOptions(t *testing.T) {
+	t.Parallel()
+	gs := NewGameWithRoller(DefaultRules(), NewFixedRoller(Moonberry, Moonberry, Moonberry, Pickleberry, Pest))
+	if err := gs.RollJars(); err != nil {
+		t.Fatalf("expected nil error but got %v", err)
+	}
+	gs.Categories.MoonberryCategory.Used = true
+
+	got := gs.ScoreOptions()
+	want := map[Category]int{
+		gs.Categories.JumbleberryCategory: 0,
+		gs.Categories.SugarberryCategory:  0,
+		gs.Categories.PickleberryCategory: 4,
+		gs.Categories.ThreeCategory:       25,
+		gs.Categories.FourCategory:        0,
+		gs.Categories.FiveCategory:        0,
+		gs.Categories.MixedCategory:       0,
+		gs.Categories.FreeCategory:        25,
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ScoreOptions() = %v, want %v", got, want)
+	}
+
+	if gs.Score != 0 || gs.Categories.FreeCategory.Used {
+		t.Errorf("ScoreOptions() changed the game state")
+	}
+}
@@ -2,18 +2,19 @@ package game
 
 // A Jar is an object that contains a die.
 type Jar struct {
-	Berry  Berry
-	Locked bool
-	Rolled bool
+	Berry  Berry `json:"berry"`
+	Locked bool  `json:"locked"`
+	Rolled bool  `json:"rolled"`
 }
 
-// Roll will roll the berry value if a Jar is unlocked, and leave the berry value unchanged if it is locked.
-func (j *Jar) Roll() {
+// Roll will roll the berry value using the provided Roller if a Jar is unlocked,
+// and leave the berry value unchanged if it is locked.
+func (j *Jar) Roll(r Roller) {
 	if j.Rolled && j.Locked {
 		return
 	}
 
-	j.Berry = doRoll()
+	j.Berry = r.Roll()
 	j.Rolled = true
 }
 
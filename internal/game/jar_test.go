@@ -52,11 +52,12 @@ func TestJar_Roll_Unlocked(t *testing.T) {
 				Rolled: tt.fields.Rolled,
 			}
 
+			roller := NewSeededRoller(0)
 			berryChanged := false
 			berry := j.Berry
 			// rolling 100 times to make sure the berry changes at least once
 			for range 100 {
-				j.Roll()
+				j.Roll(roller)
 				if j.Berry != berry {
 					berryChanged = true
 					break
@@ -99,9 +100,11 @@ func TestJar_Roll_Locked(t *testing.T) {
 				Rolled: tt.fields.Rolled,
 			}
 
+			roller := NewSeededRoller(0)
+
 			// rolling 100 times to make sure the berry doesn't change
 			for range 100 {
-				j.Roll()
+				j.Roll(roller)
 				if j.Berry != tt.fields.Berry {
 					t.Errorf("berry changed but jar was supposed to be locked")
 				}
@@ -0,0 +1,243 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// MarshalText encodes the berry as its lower case name, like "jumbleberry".
+func (b Berry) MarshalText() ([]byte, error) {
+	if b < Pest || b > Moonberry {
+		return nil, fmt.Errorf("can't encode unknown berry %d", int(b))
+	}
+
+	return []byte(strings.ToLower(b.Name())), nil
+}
+
+// UnmarshalText decodes a berry from any of the names ParseBerry accepts.
+func (b *Berry) UnmarshalText(text []byte) error {
+	berry, err := ParseBerry(string(text))
+	if err != nil {
+		return err
+	}
+
+	*b = berry
+	return nil
+}
+
+// categoryKeys holds the JSON name of every category, in scorecard order.
+var categoryKeys = [NumCategories]string{
+	"jumbleberry", "sugarberry", "pickleberry", "moonberry",
+	"threeOfAKind", "fourOfAKind", "fiveOfAKind", "mixedBasket", "freeRoll",
+}
+
+// MarshalText encodes the category as its JSON name, like "mixedBasket".
+func (id CategoryID) MarshalText() ([]byte, error) {
+	if id < 0 || id >= NumCategories {
+		return nil, fmt.Errorf("can't encode unknown category %d", int(id))
+	}
+
+	return []byte(categoryKeys[id]), nil
+}
+
+// UnmarshalText decodes a category from its JSON name or any name ParseCategory accepts.
+func (id *CategoryID) UnmarshalText(text []byte) error {
+	for i, key := range categoryKeys {
+		if string(text) == key {
+			*id = CategoryID(i)
+			return nil
+		}
+	}
+
+	parsed, err := ParseCategory(string(text))
+	if err != nil {
+		return err
+	}
+
+	*id = parsed
+	return nil
+}
+
+// categoryJSON is how a single category is stored on the JSON scorecard.
+type categoryJSON struct {
+	Score int  `json:"score"`
+	Used  bool `json:"used"`
+}
+
+// scorecardJSON is how GameCategories are stored in JSON, with the categories in scorecard order.
+type scorecardJSON struct {
+	Jumbleberry categoryJSON `json:"jumbleberry"`
+	Sugarberry  categoryJSON `json:"sugarberry"`
+	Pickleberry categoryJSON `json:"pickleberry"`
+	Moonberry   categoryJSON `json:"moonberry"`
+	Three       categoryJSON `json:"threeOfAKind"`
+	Four        categoryJSON `json:"fourOfAKind"`
+	Five        categoryJSON `json:"fiveOfAKind"`
+	Mixed       categoryJSON `json:"mixedBasket"`
+	Free        categoryJSON `json:"freeRoll"`
+}
+
+// entries returns the categories of the scorecard in the same order as GameCategories.All.
+func (s *scorecardJSON) entries() []*categoryJSON {
+	return []*categoryJSON{&s.Jumbleberry, &s.Sugarberry, &s.Pickleberry, &s.Moonberry, &s.Three, &s.Four, &s.Five, &s.Mixed, &s.Free}
+}
+
+// MarshalJSON encodes the score and used flag of every category.
+func (gc GameCategories) MarshalJSON() ([]byte, error) {
+	var s scorecardJSON
+	entries := s.entries()
+
+	for i, cat := range gc.All() {
+		if cat == nil {
+			return nil, fmt.Errorf("can't encode scorecard without a %s category", CategoryID(i))
+		}
+		*entries[i] = categoryJSON{Score: cat.GetScore(), Used: cat.IsUsed()}
+	}
+
+	return json.Marshal(s)
+}
+
+// UnmarshalJSON decodes a scorecard encoded with MarshalJSON.
+// Categories that don't exist yet are created with the default rules.
+func (gc *GameCategories) UnmarshalJSON(data []byte) error {
+	var s scorecardJSON
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	if gc.JumbleberryCategory == nil {
+		*gc = newGameCategories(nil)
+	}
+
+	for i, cat := range gc.All() {
+		entry := s.entries()[i]
+		if entry.Score < 0 || (!entry.Used && entry.Score != 0) {
+			return fmt.Errorf("invalid score %d for %s", entry.Score, CategoryID(i))
+		}
+
+		b := baseOf(cat)
+		b.Score, b.Used = entry.Score, entry.Used
+	}
+
+	return nil
+}
+
+// UnmarshalJSON decodes a GameState and checks that it is consistent with its rules.
+// The rules are decoded like ParseRules does, so missing fields keep their default and invalid rules are rejected.
+// The categories are scored with the decoded rules, and the Roller of the game state is kept.
+func (gs *GameState) UnmarshalJSON(data []byte) error {
+	// gameStateJSON has the same fields as GameState without its methods, to avoid recursing
+	type gameStateJSON GameState
+
+	// the rules are kept raw, since they're parsed with ParseRules
+	decoded := struct {
+		gameStateJSON
+		Rules json.RawMessage `json:"rules"`
+	}{gameStateJSON: gameStateJSON{Roller: gs.Roller}}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	next := GameState(decoded.gameStateJSON)
+	if len(decoded.Rules) > 0 && string(decoded.Rules) != "null" {
+		var err error
+		if next.Rules, err = ParseRules(decoded.Rules); err != nil {
+			return err
+		}
+	}
+	rules := next.ruleSet()
+
+	if next.Categories.JumbleberryCategory == nil {
+		return fmt.Errorf("game state has no scorecard")
+	}
+
+	if len(next.Jars) != rules.JarCount {
+		return fmt.Errorf("game state has %d jars, expected %d", len(next.Jars), rules.JarCount)
+	}
+
+	for i, jar := range next.Jars {
+		if jar == nil {
+			return fmt.Errorf("jar %d is missing", i)
+		}
+	}
+
+	if next.RollsLeftInTurn < 0 || next.RollsLeftInTurn > rules.RollsPerTurn {
+		return fmt.Errorf("rolls left must be between 0 and %d, got %d", rules.RollsPerTurn, next.RollsLeftInTurn)
+	}
+
+	used, score := 0, 0
+	for _, cat := range next.Categories.All() {
+		baseOf(cat).rules = next.Rules
+		if cat.IsUsed() {
+			used++
+			score += cat.GetScore()
+		}
+	}
+
+	if used != next.RoundsCompleted || score != next.Score {
+		return fmt.Errorf("scorecard has %d categories used for %d points, but the game has %d rounds completed and a score of %d", used, score, next.RoundsCompleted, next.Score)
+	}
+
+	*gs = next
+	return nil
+}
+
+// actionJSON is how an Action is stored in JSON.
+// Rerolls list the kept jars, like {"kind":"reroll","keep":[0,3]}, and scores name the category,
+// like {"kind":"score","category":"mixedBasket"}.
+type actionJSON struct {
+	Kind     string      `json:"kind"`
+	Keep     []int       `json:"keep,omitempty"`
+	Category *CategoryID `json:"category,omitempty"`
+}
+
+// MarshalJSON encodes the action with only the fields its kind uses.
+func (a Action) MarshalJSON() ([]byte, error) {
+	switch a.Kind {
+	case Reroll:
+		// the kept jars are always listed, even when none are kept
+		out := struct {
+			Kind string `json:"kind"`
+			Keep []int  `json:"keep"`
+		}{Kind: "reroll", Keep: []int{}}
+
+		for jar := range 64 {
+			if a.Keeps(jar) {
+				out.Keep = append(out.Keep, jar)
+			}
+		}
+		return json.Marshal(out)
+	case Score:
+		return json.Marshal(actionJSON{Kind: "score", Category: &a.Category})
+	default:
+		return nil, fmt.Errorf("can't encode unknown action kind %d", a.Kind)
+	}
+}
+
+// UnmarshalJSON decodes an action encoded with MarshalJSON.
+func (a *Action) UnmarshalJSON(data []byte) error {
+	var in actionJSON
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+
+	switch in.Kind {
+	case "reroll":
+		for _, jar := range in.Keep {
+			if jar < 0 || jar >= 64 {
+				return fmt.Errorf("invalid jar %d", jar)
+			}
+		}
+		*a = NewReroll(in.Keep...)
+	case "score":
+		if in.Category == nil {
+			return fmt.Errorf("score action has no category")
+		}
+		*a = NewScore(*in.Category)
+	default:
+		return fmt.Errorf("unknown action kind %q", in.Kind)
+	}
+
+	return nil
+}
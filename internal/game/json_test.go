@@ -0,0 +1,222 @@
+package game
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBerry_JSON(t *testing.T) {
+	t.Parallel()
+	berries := []Berry{Pest, Jumbleberry, Sugarberry, Pickleberry, Moonberry}
+
+	data, err := json.Marshal(berries)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	want := `["pest","jumbleberry","sugarberry","pickleberry","moonberry"]`
+	if string(data) != want {
+		t.Errorf("Marshal() = %s, want %s", data, want)
+	}
+
+	var got []Berry
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(got, berries) {
+		t.Errorf("Unmarshal() = %v, want %v", got, berries)
+	}
+
+	if _, err := json.Marshal(Berry(9)); err == nil {
+		t.Errorf("Marshal() expected an error for an unknown berry")
+	}
+
+	var b Berry
+	if err := json.Unmarshal([]byte(`"blueberry"`), &b); err == nil {
+		t.Errorf("Unmarshal() expected an error for an unknown berry")
+	}
+}
+
+func TestGameCategories_JSON(t *testing.T) {
+	t.Parallel()
+	gc := newGameCategories(nil)
+	gc.MixedCategory.CalcScore([]Berry{Jumbleberry, Sugarberry, Pickleberry, Moonberry, Pest})
+
+	data, err := json.Marshal(gc)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	want := `{"jumbleberry":{"score":0,"used":false},"sugarberry":{"score":0,"used":false},` +
+		`"pickleberry":{"score":0,"used":false},"moonberry":{"score":0,"used":false},` +
+		`"threeOfAKind":{"score":0,"used":false},"fourOfAKind":{"score":0,"used":false},` +
+		`"fiveOfAKind":{"score":0,"used":false},"mixedBasket":{"score":15,"used":true},"freeRoll":{"score":0,"used":false}}`
+	if string(data) != want {
+		t.Errorf("Marshal() = %s, want %s", data, want)
+	}
+
+	var got GameCategories
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(got, gc) {
+		t.Errorf("Unmarshal() = %+v, want %+v", got, gc)
+	}
+
+	if err := json.Unmarshal([]byte(`{"freeRoll":{"score":12,"used":false}}`), &got); err == nil {
+		t.Errorf("Unmarshal() expected an error for a score in an unused category")
+	}
+}
+
+func TestGameState_JSON(t *testing.T) {
+	t.Parallel()
+	rules := DefaultRules()
+	rules.RollsPerTurn = 4
+
+	gs := NewGameWithRoller(rules, NewSeededRoller(1))
+	gs.RollJars()
+	for _, action := range []Action{NewReroll(0, 2), NewScore(CatFree), NewReroll(1), NewScore(CatMixed), NewReroll(3, 4)} {
+		if err := gs.Apply(action); err != nil {
+			t.Fatalf("Apply() error = %v", err)
+		}
+	}
+
+	data, err := json.Marshal(gs)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	for _, key := range []string{`"scorecard":`, `"jars":[{"berry":"`, `"rollsLeft":2`, `"roundsCompleted":2`, `"rules":{`} {
+		if !strings.Contains(string(data), key) {
+			t.Errorf("Marshal() = %s, doesn't contain %s", data, key)
+		}
+	}
+
+	got := &GameState{}
+	if err := json.Unmarshal(data, got); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	gs.Roller = nil
+	if !reflect.DeepEqual(got, gs) {
+		t.Errorf("Unmarshal() = %v, want %v", got, gs)
+	}
+
+	// the decoded game keeps being played with its own rules
+	if got.Categories.FreeCategory.ruleSet().RollsPerTurn != 4 {
+		t.Errorf("decoded categories aren't scored with the decoded rules")
+	}
+
+	if err := got.Apply(NewReroll()); err != nil {
+		t.Errorf("Apply() on the decoded game error = %v", err)
+	}
+}
+
+func TestGameState_UnmarshalJSON_Invalid(t *testing.T) {
+	t.Parallel()
+	valid, err := json.Marshal(NewGame(nil))
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		replace [2]string
+	}{
+		{name: "Too few jars", replace: [2]string{`{"berry":"pest","locked":false,"rolled":false},`, ``}},
+		{name: "Too many rolls left", replace: [2]string{`"rollsLeft":3`, `"rollsLeft":4`}},
+		{name: "Score doesn't match scorecard", replace: [2]string{`"score":0,"rules"`, `"score":5,"rules"`}},
+		{name: "Rounds don't match scorecard", replace: [2]string{`"roundsCompleted":0`, `"roundsCompleted":1`}},
+		{name: "Unknown berry", replace: [2]string{`"berry":"pest"`, `"berry":"grape"`}},
+		{name: "Invalid rules", replace: [2]string{`"pest":0.1}`, `"pest":-1}`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			data := strings.Replace(string(valid), tt.replace[0], tt.replace[1], 1)
+			if data == string(valid) {
+				t.Fatalf("test case didn't change %s", valid)
+			}
+
+			var gs GameState
+			if err := json.Unmarshal([]byte(data), &gs); err == nil {
+				t.Errorf("Unmarshal() expected an error but got nil")
+			}
+		})
+	}
+}
+
+func TestGameState_UnmarshalJSON_PartialRules(t *testing.T) {
+	t.Parallel()
+	data, err := json.Marshal(NewGame(nil))
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	// rules that only set the rolls per turn keep the other defaults, like ParseRules
+	start := strings.Index(string(data), `"rules":`)
+	partial := string(data[:start]) + `"rules":{"rollsPerTurn":4}}`
+
+	var gs GameState
+	if err := json.Unmarshal([]byte(partial), &gs); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	want := DefaultRules()
+	want.RollsPerTurn = 4
+	if !reflect.DeepEqual(gs.Rules, want) {
+		t.Errorf("Unmarshal() rules = %+v, want %+v", gs.Rules, want)
+	}
+}
+
+func TestAction_JSON(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		action Action
+		want   string
+	}{
+		{name: "Reroll", action: NewReroll(0, 3), want: `{"kind":"reroll","keep":[0,3]}`},
+		{name: "Reroll everything", action: NewReroll(), want: `{"kind":"reroll","keep":[]}`},
+		{name: "Score", action: NewScore(CatMixed), want: `{"kind":"score","category":"mixedBasket"}`},
+		{name: "Score the first category", action: NewScore(CatJumbleberry), want: `{"kind":"score","category":"jumbleberry"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			data, err := json.Marshal(tt.action)
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+
+			if string(data) != tt.want {
+				t.Errorf("Marshal() = %s, want %s", data, tt.want)
+			}
+
+			var got Action
+			if err := json.Unmarshal(data, &got); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+
+			if got != tt.action {
+				t.Errorf("Unmarshal() = %v, want %v", got, tt.action)
+			}
+		})
+	}
+
+	for _, invalid := range []string{`{"kind":"pass"}`, `{"kind":"score"}`, `{"kind":"score","category":"bonus"}`, `{"kind":"reroll","keep":[-1]}`} {
+		var a Action
+		if err := json.Unmarshal([]byte(invalid), &a); err == nil {
+			t.Errorf("Unmarshal(%s) expected an error but got nil", invalid)
+		}
+	}
+
+	var a Action
+	if err := json.Unmarshal([]byte(`{"kind":"score","category":"mixed"}`), &a); err != nil || a != NewScore(CatMixed) {
+		t.Errorf("Unmarshal() of a category prefix = %v, %v", a, err)
+	}
+}
@@ -0,0 +1,175 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+)
+
+// ErrNotYourTurn is returned when a player tries to act while it is another player's turn.
+var ErrNotYourTurn = errors.New("it isn't your turn")
+
+// A Match is a game between several players who take turns in a fixed order, like on the physical board.
+// Every player has their own scorecard and plays a full turn before passing the jars to the next player.
+//
+// The games of the players can only be changed through Apply, which checks the turn order.
+type Match struct {
+	Players []*MatchPlayer `json:"players"`
+
+	// active is the index of the player whose turn it is.
+	active int
+
+	// Roller is shared by every player, so the rolls are drawn in turn order.
+	Roller Roller `json:"-"`
+}
+
+// A MatchPlayer is one player in a Match and their own game.
+type MatchPlayer struct {
+	Name  string
+	state *GameState
+}
+
+// State returns a copy of the player's game, which can't be used to act in the match.
+func (p *MatchPlayer) State() *GameState {
+	return p.state.Clone()
+}
+
+// Score returns the player's score so far.
+func (p *MatchPlayer) Score() int {
+	return p.state.Score
+}
+
+// MarshalJSON encodes the player's name and game.
+func (p *MatchPlayer) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Name  string     `json:"name"`
+		State *GameState `json:"state"`
+	}{Name: p.Name, State: p.state})
+}
+
+// A Standing is the final position of a player in a Match.
+type Standing struct {
+	// Player is the index of the player in the match.
+	Player int    `json:"player"`
+	Name   string `json:"name"`
+	Score  int    `json:"score"`
+
+	// Rank starts at 1. Tied players share a rank and the next rank is skipped, so scores 50, 40, 40, 30 rank 1, 2, 2, 4.
+	Rank int `json:"rank"`
+}
+
+// NewMatch returns a match between the named players that draws all of its rolls from r, and rolls the jars for the first player.
+// If rules is nil, DefaultRules are used. If r is nil, a randomly seeded Roller is used.
+func NewMatch(rules *RuleSet, names []string, r Roller) (*Match, error) {
+	if len(names) == 0 {
+		return nil, fmt.Errorf("a match needs at least one player")
+	}
+
+	if rules == nil {
+		rules = DefaultRules()
+	}
+
+	if r == nil {
+		r = rules.NewRoller(rand.Int63())
+	}
+
+	m := &Match{Roller: r}
+	seen := make(map[string]bool)
+	for _, name := range names {
+		if seen[name] {
+			return nil, fmt.Errorf("player %q is in the match twice", name)
+		}
+		seen[name] = true
+
+		m.Players = append(m.Players, &MatchPlayer{Name: name, state: NewGameWithRoller(rules, r)})
+	}
+
+	m.Players[0].state.RollJars()
+
+	return m, nil
+}
+
+// MarshalJSON encodes the players and whose turn it is.
+func (m *Match) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Players []*MatchPlayer `json:"players"`
+		Active  int            `json:"active"`
+	}{Players: m.Players, Active: m.active})
+}
+
+// Active returns the index of the player whose turn it is.
+func (m *Match) Active() int {
+	return m.active
+}
+
+// Current returns the player whose turn it is.
+func (m *Match) Current() *MatchPlayer {
+	return m.Players[m.active]
+}
+
+// IsOver returns true once every player has scored every category.
+func (m *Match) IsOver() bool {
+	for _, p := range m.Players {
+		if !p.state.IsOver() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Apply validates the action and applies it to the game of the provided player.
+// It returns ErrNotYourTurn if it isn't the player's turn. Scoring a category ends the turn, and
+// the jars are rolled for the next player.
+func (m *Match) Apply(player int, a Action) error {
+	if player < 0 || player >= len(m.Players) {
+		return fmt.Errorf("invalid player %d", player)
+	}
+
+	if m.IsOver() {
+		return fmt.Errorf("match is over")
+	}
+
+	if player != m.active {
+		return fmt.Errorf("%s can't act while it is %s's turn: %w", m.Players[player].Name, m.Current().Name, ErrNotYourTurn)
+	}
+
+	if err := m.Current().state.apply(a, false); err != nil {
+		return err
+	}
+
+	if a.Kind == Score {
+		m.active = (m.active + 1) % len(m.Players)
+		if !m.IsOver() {
+			return m.Current().state.RollJars()
+		}
+	}
+
+	return nil
+}
+
+// Standings returns every player ordered by score, highest first. Players with the same score are
+// ordered by their turn order and share a rank.
+// The standings can be read during a match, but they are only final once it is over.
+func (m *Match) Standings() []Standing {
+	standings := make([]Standing, len(m.Players))
+	for i, p := range m.Players {
+		standings[i] = Standing{Player: i, Name: p.Name, Score: p.Score()}
+	}
+
+	sort.SliceStable(standings, func(i, j int) bool {
+		return standings[i].Score > standings[j].Score
+	})
+
+	for i := range standings {
+		if i > 0 && standings[i].Score == standings[i-1].Score {
+			standings[i].Rank = standings[i-1].Rank
+		} else {
+			standings[i].Rank = i + 1
+		}
+	}
+
+	return standings
+}
@@ -0,0 +1,173 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestMatch_TurnOrder(t *testing.T) {
+	t.Parallel()
+	roller := NewFixedRoller(
+		Moonberry, Moonberry, Moonberry, Moonberry, Moonberry, // alice's first roll
+		Pest, Pest, Pest, Pest, Pest, // bob's first roll
+		Sugarberry, Sugarberry, Sugarberry, // bob rerolls jars 2 to 4
+		Jumbleberry, Jumbleberry, Jumbleberry, Jumbleberry, Jumbleberry, // alice's second turn
+	)
+	m, err := NewMatch(nil, []string{"alice", "bob"}, roller)
+	if err != nil {
+		t.Fatalf("NewMatch() error = %v", err)
+	}
+
+	if err := m.Apply(1, NewReroll()); !errors.Is(err, ErrNotYourTurn) {
+		t.Errorf("Apply() by the inactive player error = %v, want ErrNotYourTurn", err)
+	}
+
+	if err := m.Apply(0, NewScore(CatFive)); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+
+	// scoring passes the jars to bob, and alice's next turn isn't rolled until bob is done
+	if m.Current().Name != "bob" || m.Players[0].state.hasRolled() {
+		t.Fatalf("after alice scored, Current() = %s and alice has rolled = %v", m.Current().Name, m.Players[0].state.hasRolled())
+	}
+
+	if err := m.Apply(0, NewReroll()); !errors.Is(err, ErrNotYourTurn) {
+		t.Errorf("Apply() by the inactive player error = %v, want ErrNotYourTurn", err)
+	}
+
+	for _, a := range []Action{NewReroll(0, 1), NewScore(CatSugarberry)} {
+		if err := m.Apply(1, a); err != nil {
+			t.Fatalf("Apply() error = %v", err)
+		}
+	}
+
+	if m.Current().Name != "alice" {
+		t.Errorf("Current() = %s, want the turn to return to alice", m.Current().Name)
+	}
+
+	want := []Berry{Jumbleberry, Jumbleberry, Jumbleberry, Jumbleberry, Jumbleberry}
+	if got := m.Players[0].State().GetBerries(); !reflect.DeepEqual(got, want) {
+		t.Errorf("alice's second turn rolled %v, want %v", got, want)
+	}
+
+	if m.Players[0].Score() != 35 || m.Players[1].Score() != 6 {
+		t.Errorf("scores = %d and %d, want 35 and 6", m.Players[0].Score(), m.Players[1].Score())
+	}
+
+	if err := m.Apply(2, NewReroll()); err == nil {
+		t.Errorf("Apply() expected an error for an invalid player")
+	}
+}
+
+func TestMatch_PlayToEnd(t *testing.T) {
+	t.Parallel()
+	m, err := NewMatch(nil, []string{"a", "b", "c"}, NewSeededRoller(3))
+	if err != nil {
+		t.Fatalf("NewMatch() error = %v", err)
+	}
+
+	turns := 0
+	for !m.IsOver() {
+		if err := m.Apply(m.Active(), NewScore(CategoryID(turns/len(m.Players)))); err != nil {
+			t.Fatalf("Apply() error = %v", err)
+		}
+		turns++
+	}
+
+	if turns != 3*NumCategories {
+		t.Errorf("match took %d turns, want %d", turns, 3*NumCategories)
+	}
+
+	for _, p := range m.Players {
+		if !p.State().IsOver() {
+			t.Errorf("player %s hasn't finished", p.Name)
+		}
+	}
+
+	if err := m.Apply(m.Active(), NewReroll()); err == nil {
+		t.Errorf("Apply() expected an error once the match is over")
+	}
+}
+
+func TestMatch_Standings(t *testing.T) {
+	t.Parallel()
+	m, err := NewMatch(nil, []string{"a", "b", "c", "d"}, NewSeededRoller(1))
+	if err != nil {
+		t.Fatalf("NewMatch() error = %v", err)
+	}
+
+	for i, score := range []int{40, 50, 40, 30} {
+		m.Players[i].state.Score = score
+	}
+
+	want := []Standing{
+		{Player: 1, Name: "b", Score: 50, Rank: 1},
+		{Player: 0, Name: "a", Score: 40, Rank: 2},
+		{Player: 2, Name: "c", Score: 40, Rank: 2},
+		{Player: 3, Name: "d", Score: 30, Rank: 4},
+	}
+	if got := m.Standings(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Standings() = %+v, want %+v", got, want)
+	}
+}
+
+func TestNewMatch_Invalid(t *testing.T) {
+	t.Parallel()
+	if _, err := NewMatch(nil, nil, nil); err == nil {
+		t.Errorf("NewMatch() expected an error without players")
+	}
+
+	if _, err := NewMatch(nil, []string{"a", "a"}, nil); err == nil {
+		t.Errorf("NewMatch() expected an error for a duplicate player")
+	}
+}
+
+func TestMatch_StateIsACopy(t *testing.T) {
+	t.Parallel()
+	m, err := NewMatch(nil, []string{"alice", "bob"}, NewSeededRoller(4))
+	if err != nil {
+		t.Fatalf("NewMatch() error = %v", err)
+	}
+
+	// acting on bob's state directly doesn't get around the turn order
+	bob := m.Players[1].State()
+	if err := bob.Apply(NewScore(CatFree)); err == nil {
+		t.Fatalf("Apply() expected an error before bob's jars are rolled")
+	}
+
+	alice := m.Players[0].State()
+	want := alice.GetBerries()
+	if err := alice.Apply(NewScore(CatFree)); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+
+	if got := m.Players[0].State(); got.Score != 0 || got.RoundsCompleted != 0 || !reflect.DeepEqual(got.GetBerries(), want) {
+		t.Errorf("scoring a copy of alice's state changed the match: %+v", got)
+	}
+
+	if m.Active() != 0 {
+		t.Errorf("Active() = %d, want 0", m.Active())
+	}
+
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	var decoded struct {
+		Players []struct {
+			Name  string    `json:"name"`
+			State GameState `json:"state"`
+		} `json:"players"`
+		Active int `json:"active"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	if len(decoded.Players) != 2 || decoded.Players[1].Name != "bob" || decoded.Active != 0 {
+		t.Errorf("match encoded as %s", data)
+	}
+}
@@ -0,0 +1,64 @@
+package game
+
+import "fmt"
+
+// A Position describes a point in a game, for example one read off a physical board,
+// so it can be turned into a GameState without replaying the game.
+type Position struct {
+	// Berries holds the berry in every jar.
+	Berries []Berry
+
+	// RollsLeft is how many rolls are left in the current turn. The jars have always been rolled at least once.
+	RollsLeft int
+
+	// Used holds the points scored in every category that has already been used.
+	Used map[CategoryID]int
+}
+
+// NewGameFromPosition returns a *GameState at the provided position.
+// The score is the sum of the points in the used categories, and one round has been completed per used category.
+// If rules is nil, DefaultRules are used.
+func NewGameFromPosition(rules *RuleSet, p Position) (*GameState, error) {
+	gs := NewGame(rules)
+	rules = gs.ruleSet()
+
+	if len(p.Berries) != rules.JarCount {
+		return nil, fmt.Errorf("position has %d berries, expected %d", len(p.Berries), rules.JarCount)
+	}
+
+	if p.RollsLeft < 0 || p.RollsLeft >= rules.RollsPerTurn {
+		return nil, fmt.Errorf("rolls left must be between 0 and %d, got %d", rules.RollsPerTurn-1, p.RollsLeft)
+	}
+
+	if len(p.Used) >= NumCategories {
+		return nil, fmt.Errorf("every category has been used, the game is over")
+	}
+
+	for i, berry := range p.Berries {
+		if berry < Pest || berry > Moonberry {
+			return nil, fmt.Errorf("invalid berry %d in jar %d", berry, i)
+		}
+
+		gs.Jars[i].Berry = berry
+		gs.Jars[i].Rolled = true
+	}
+
+	for id, points := range p.Used {
+		cat := gs.Categories.Get(id)
+		if cat == nil {
+			return nil, fmt.Errorf("invalid category %d", id)
+		}
+
+		if points < 0 {
+			return nil, fmt.Errorf("%s can't have scored %d points", id, points)
+		}
+
+		baseOf(cat).record(points)
+		gs.Score += points
+	}
+
+	gs.RollsLeftInTurn = p.RollsLeft
+	gs.RoundsCompleted = len(p.Used)
+
+	return gs, nil
+}
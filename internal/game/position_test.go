@@ -0,0 +1,71 @@
+package game
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewGameFromPosition(t *testing.T) {
+	t.Parallel()
+	berries := []Berry{Moonberry, Moonberry, Moonberry, Pest, Sugarberry}
+
+	gs, err := NewGameFromPosition(nil, Position{
+		Berries:   berries,
+		RollsLeft: 1,
+		Used:      map[CategoryID]int{CatMixed: 12, CatFive: 0},
+	})
+	if err != nil {
+		t.Fatalf("NewGameFromPosition() error = %v", err)
+	}
+
+	if got := gs.GetBerries(); !reflect.DeepEqual(got, berries) {
+		t.Errorf("GetBerries() = %v, want %v", got, berries)
+	}
+
+	if gs.Score != 12 || gs.RoundsCompleted != 2 || gs.RollsLeftInTurn != 1 {
+		t.Errorf("score %d, rounds %d, rolls left %d, want 12, 2 and 1", gs.Score, gs.RoundsCompleted, gs.RollsLeftInTurn)
+	}
+
+	if !gs.Categories.MixedCategory.Used || gs.Categories.MixedCategory.Score != 12 || !gs.Categories.FiveCategory.Used {
+		t.Errorf("used categories weren't recorded: %v", gs)
+	}
+
+	// the position must be playable like any other game
+	if err := gs.Apply(NewReroll(0, 1, 2)); err != nil {
+		t.Errorf("Apply() error = %v", err)
+	}
+
+	if got := gs.GetBerries()[:3]; !reflect.DeepEqual(got, berries[:3]) {
+		t.Errorf("kept berries = %v, want %v", got, berries[:3])
+	}
+}
+
+func TestNewGameFromPosition_Invalid(t *testing.T) {
+	t.Parallel()
+	five := []Berry{Pest, Pest, Pest, Pest, Pest}
+	allUsed := make(map[CategoryID]int)
+	for id := range CategoryID(NumCategories) {
+		allUsed[id] = 0
+	}
+
+	tests := []struct {
+		name     string
+		position Position
+	}{
+		{name: "Too few berries", position: Position{Berries: five[:4]}},
+		{name: "Invalid berry", position: Position{Berries: []Berry{Pest, Pest, Pest, Pest, Berry(9)}}},
+		{name: "Too many rolls left", position: Position{Berries: five, RollsLeft: 3}},
+		{name: "Negative rolls left", position: Position{Berries: five, RollsLeft: -1}},
+		{name: "Invalid category", position: Position{Berries: five, Used: map[CategoryID]int{CategoryID(12): 0}}},
+		{name: "Negative points", position: Position{Berries: five, Used: map[CategoryID]int{CatFree: -1}}},
+		{name: "Game over", position: Position{Berries: five, Used: allUsed}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if _, err := NewGameFromPosition(nil, tt.position); err == nil {
+				t.Errorf("NewGameFromPosition() expected an error but got nil")
+			}
+		})
+	}
+}
@@ -2,30 +2,96 @@ package game
 
 import "math/rand"
 
-// doRoll rolls a single die and returns the result.
-func doRoll() Berry {
-	roll := rand.Float64()
-	switch {
-	case roll < 0.3:
-		return Jumbleberry
-	case roll < 0.6:
-		return Sugarberry
-	case roll < 0.8:
-		return Pickleberry
-	case roll < 0.9:
-		return Moonberry
-	default:
-		return Pest
+// A Roller is a source of die rolls.
+// Every GameState draws its rolls from a Roller so that games can be reproduced or scripted.
+type Roller interface {
+	Roll() Berry
+}
+
+// A SeededRoller rolls dice using its own pseudo-random number generator.
+// Two SeededRollers created with the same seed and weights will produce the same sequence of rolls.
+type SeededRoller struct {
+	rng     *rand.Rand
+	weights FaceWeights
+	total   float64
+}
+
+// NewSeededRoller returns a SeededRoller whose rolls are determined by the provided seed.
+// Faces are rolled with the odds of the standard rules.
+func NewSeededRoller(seed int64) *SeededRoller {
+	return NewWeightedRoller(seed, DefaultRules().FaceWeights)
+}
+
+// NewWeightedRoller returns a SeededRoller that rolls faces with the provided odds.
+func NewWeightedRoller(seed int64, weights FaceWeights) *SeededRoller {
+	total := 0.0
+	for _, berry := range faceOrder {
+		total += weights.Of(berry)
+	}
+
+	return &SeededRoller{
+		rng:     rand.New(rand.NewSource(seed)),
+		weights: weights,
+		total:   total,
 	}
 }
 
-// doRolls rolls n dice and returns the results in a slice.
-func DoRolls(n int)[]Berry {
+// NewRandomRoller returns a SeededRoller with a randomly chosen seed.
+func NewRandomRoller() *SeededRoller {
+	return NewSeededRoller(rand.Int63())
+}
+
+// Roll rolls a single die and returns the result.
+func (s *SeededRoller) Roll() Berry {
+	roll := s.rng.Float64() * s.total
+
+	cumulative := 0.0
+	for _, berry := range faceOrder {
+		cumulative += s.weights.Of(berry)
+		if roll < cumulative {
+			return berry
+		}
+	}
+
+	return faceOrder[len(faceOrder)-1]
+}
+
+// A FixedRoller returns a scripted sequence of berries, in order.
+// It panics if more rolls are requested than were scripted.
+type FixedRoller struct {
+	Berries []Berry
+	next    int
+}
+
+// NewFixedRoller returns a FixedRoller that will roll the provided berries in order.
+func NewFixedRoller(berries ...Berry) *FixedRoller {
+	return &FixedRoller{Berries: berries}
+}
+
+// Roll returns the next berry in the sequence.
+func (f *FixedRoller) Roll() Berry {
+	if f.next >= len(f.Berries) {
+		panic("fixed roller has no rolls left")
+	}
+
+	berry := f.Berries[f.next]
+	f.next++
+
+	return berry
+}
+
+// Remaining returns the number of scripted rolls that have not been used yet.
+func (f *FixedRoller) Remaining() int {
+	return len(f.Berries) - f.next
+}
+
+// DoRolls rolls n dice using the provided Roller and returns the results in a slice.
+func DoRolls(r Roller, n int) []Berry {
 	result := make([]Berry, n)
 
 	for i := range n {
-		result[i] = doRoll()
+		result[i] = r.Roll()
 	}
 
 	return result
-}
\ No newline at end of file
+}
@@ -1,19 +1,21 @@
 package game
 
 import (
+	"reflect"
 	"testing"
 )
 
 // this test will do 10000 random rolls and ensure that the number of each result is within
 // 5 standard deviations of what is expected
 // there is a VERY small chance this test will fail by random chance
-func Test_doRoll(t *testing.T) {
+func TestSeededRoller_Roll(t *testing.T) {
 	t.Parallel()
 
+	roller := NewRandomRoller()
 	berryCounts := make(map[Berry]int)
 
 	for range 10000 {
-		result := doRoll()
+		result := roller.Roll()
 		if count, ok := berryCounts[result]; ok {
 			berryCounts[result] = count + 1
 		} else {
@@ -38,6 +40,41 @@ func Test_doRoll(t *testing.T) {
 	}
 }
 
+func TestSeededRoller_SameSeed(t *testing.T) {
+	t.Parallel()
+
+	a := DoRolls(NewSeededRoller(42), 100)
+	b := DoRolls(NewSeededRoller(42), 100)
+
+	if !reflect.DeepEqual(a, b) {
+		t.Errorf("rollers with the same seed produced different rolls")
+	}
+}
+
+func TestFixedRoller_Roll(t *testing.T) {
+	t.Parallel()
+
+	roller := NewFixedRoller(Moonberry, Pest, Jumbleberry)
+
+	got := DoRolls(roller, 3)
+	want := []Berry{Moonberry, Pest, Jumbleberry}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FixedRoller rolled %v, want %v", got, want)
+	}
+
+	if roller.Remaining() != 0 {
+		t.Errorf("expected no rolls remaining, got %d", roller.Remaining())
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected panic when the fixed roller is exhausted")
+		}
+	}()
+	roller.Roll()
+}
+
 func TestDoRolls(t *testing.T) {
 	type args struct {
 		n int
@@ -61,7 +98,7 @@ func TestDoRolls(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := DoRolls(tt.args.n)
+			got := DoRolls(NewRandomRoller(), tt.args.n)
 
 			if len(got) != tt.args.n {
 				t.Errorf("expected to get slice of len %d, got %d", tt.args.n, len(got))
@@ -0,0 +1,177 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// FaceWeights holds the relative likelihood of rolling each face of a die.
+// The weights don't need to add up to 1, they are normalized when rolling.
+type FaceWeights struct {
+	Jumbleberry float64 `json:"jumbleberry"`
+	Sugarberry  float64 `json:"sugarberry"`
+	Pickleberry float64 `json:"pickleberry"`
+	Moonberry   float64 `json:"moonberry"`
+	Pest        float64 `json:"pest"`
+}
+
+// Of returns the weight of the provided berry.
+func (w FaceWeights) Of(b Berry) float64 {
+	switch b {
+	case Jumbleberry:
+		return w.Jumbleberry
+	case Sugarberry:
+		return w.Sugarberry
+	case Pickleberry:
+		return w.Pickleberry
+	case Moonberry:
+		return w.Moonberry
+	case Pest:
+		return w.Pest
+	default:
+		return 0
+	}
+}
+
+// BerryPoints holds a number of points for each face of a die.
+type BerryPoints struct {
+	Jumbleberry int `json:"jumbleberry"`
+	Sugarberry  int `json:"sugarberry"`
+	Pickleberry int `json:"pickleberry"`
+	Moonberry   int `json:"moonberry"`
+	Pest        int `json:"pest"`
+}
+
+// Of returns the points for the provided berry.
+func (p BerryPoints) Of(b Berry) int {
+	switch b {
+	case Jumbleberry:
+		return p.Jumbleberry
+	case Sugarberry:
+		return p.Sugarberry
+	case Pickleberry:
+		return p.Pickleberry
+	case Moonberry:
+		return p.Moonberry
+	case Pest:
+		return p.Pest
+	default:
+		return 0
+	}
+}
+
+// A RuleSet holds the tunable rules of a game: the odds of each face, how many points each berry is worth,
+// the Five of a Kind payouts, the number of rolls per turn and the number of jars.
+type RuleSet struct {
+	// FaceWeights is the relative likelihood of rolling each face.
+	FaceWeights FaceWeights `json:"faceWeights"`
+
+	// Points is how many points each berry is worth when it is counted towards a category.
+	Points BerryPoints `json:"points"`
+
+	// FiveOfAKind is the score awarded by the Five of a Kind category for five of each type of berry.
+	FiveOfAKind BerryPoints `json:"fiveOfAKind"`
+
+	// RollsPerTurn is how many times the jars can be rolled in a single turn.
+	RollsPerTurn int `json:"rollsPerTurn"`
+
+	// JarCount is the number of jars (dice) in the game.
+	JarCount int `json:"jarCount"`
+}
+
+// DefaultRules returns the RuleSet for the standard game of Jumbleberry Fields.
+func DefaultRules() *RuleSet {
+	return &RuleSet{
+		FaceWeights: FaceWeights{
+			Jumbleberry: 0.3,
+			Sugarberry:  0.3,
+			Pickleberry: 0.2,
+			Moonberry:   0.1,
+			Pest:        0.1,
+		},
+		Points: BerryPoints{
+			Jumbleberry: 2,
+			Sugarberry:  2,
+			Pickleberry: 4,
+			Moonberry:   7,
+		},
+		FiveOfAKind: BerryPoints{
+			Jumbleberry: 10,
+			Sugarberry:  10,
+			Pickleberry: 20,
+			Moonberry:   35,
+		},
+		RollsPerTurn: 3,
+		JarCount:     5,
+	}
+}
+
+// ParseRules parses a RuleSet from JSON.
+// Any fields that are missing from the JSON keep their value from DefaultRules.
+func ParseRules(data []byte) (*RuleSet, error) {
+	rules := DefaultRules()
+	if err := json.Unmarshal(data, rules); err != nil {
+		return nil, fmt.Errorf("error parsing rules: %w", err)
+	}
+
+	if err := rules.Validate(); err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+// LoadRules reads a RuleSet from the JSON file at path.
+func LoadRules(path string) (*RuleSet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading rules: %w", err)
+	}
+
+	return ParseRules(data)
+}
+
+// Validate returns an error if the RuleSet can't be used to play a game.
+func (r *RuleSet) Validate() error {
+	total := 0.0
+	for _, berry := range faceOrder {
+		weight := r.FaceWeights.Of(berry)
+		if weight < 0 {
+			return fmt.Errorf("face weights must not be negative, got %v", weight)
+		}
+		total += weight
+	}
+
+	if total <= 0 {
+		return fmt.Errorf("face weights must add up to more than zero")
+	}
+
+	if r.RollsPerTurn < 1 {
+		return fmt.Errorf("rolls per turn must be at least 1, got %d", r.RollsPerTurn)
+	}
+
+	if r.JarCount < 1 {
+		return fmt.Errorf("jar count must be at least 1, got %d", r.JarCount)
+	}
+
+	return nil
+}
+
+// NewRoller returns a SeededRoller that rolls faces with the odds of the RuleSet.
+func (r *RuleSet) NewRoller(seed int64) *SeededRoller {
+	return NewWeightedRoller(seed, r.FaceWeights)
+}
+
+// SumPoints returns the total points of all of the provided berries.
+func (r *RuleSet) SumPoints(berries []Berry) int {
+	score := 0
+	for _, berry := range berries {
+		score += r.Points.Of(berry)
+	}
+
+	return score
+}
+
+// faceOrder is the order in which faces are laid out when mapping a random number to a face.
+var faceOrder = []Berry{Jumbleberry, Sugarberry, Pickleberry, Moonberry, Pest}
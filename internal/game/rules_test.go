@@ -0,0 +1,123 @@
+package game
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseRules(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		data    string
+		want    func() *RuleSet
+		wantErr bool
+	}{
+		{
+			name: "Empty object keeps the defaults",
+			data: `{}`,
+			want: DefaultRules,
+		},
+		{
+			name: "Partial override",
+			data: `{"points": {"moonberry": 9}, "rollsPerTurn": 4}`,
+			want: func() *RuleSet {
+				rules := DefaultRules()
+				rules.Points.Moonberry = 9
+				rules.RollsPerTurn = 4
+				return rules
+			},
+		},
+		{
+			name:    "Negative weight",
+			data:    `{"faceWeights": {"pest": -1}}`,
+			wantErr: true,
+		},
+		{
+			name:    "All weights zero",
+			data:    `{"faceWeights": {"jumbleberry": 0, "sugarberry": 0, "pickleberry": 0, "moonberry": 0, "pest": 0}}`,
+			wantErr: true,
+		},
+		{
+			name:    "No jars",
+			data:    `{"jarCount": 0}`,
+			wantErr: true,
+		},
+		{
+			name:    "Invalid JSON",
+			data:    `{"jarCount": `,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := ParseRules([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRules() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if want := tt.want(); !reflect.DeepEqual(got, want) {
+				t.Errorf("ParseRules() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestLoadRules(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "rules.json")
+	if err := os.WriteFile(path, []byte(`{"jarCount": 6}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	rules, err := LoadRules(path)
+	if err != nil {
+		t.Fatalf("expected nil error but got %v", err)
+	}
+
+	if rules.JarCount != 6 {
+		t.Errorf("expected 6 jars, got %d", rules.JarCount)
+	}
+
+	if _, err := LoadRules(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Errorf("expected error for missing file but got nil")
+	}
+}
+
+func TestNewWeightedRoller(t *testing.T) {
+	t.Parallel()
+	roller := NewWeightedRoller(0, FaceWeights{Moonberry: 1})
+
+	for _, berry := range DoRolls(roller, 100) {
+		if berry != Moonberry {
+			t.Fatalf("expected only Moonberries, got %v", berry)
+		}
+	}
+}
+
+func TestHouseRulesScoring(t *testing.T) {
+	t.Parallel()
+	rules := DefaultRules()
+	rules.Points.Moonberry = 10
+	rules.FiveOfAKind.Moonberry = 100
+	gs := NewGame(rules)
+
+	berries := []Berry{Moonberry, Moonberry, Moonberry, Moonberry, Moonberry}
+
+	if got, _ := gs.Categories.MoonberryCategory.CalcScore(berries); got != 50 {
+		t.Errorf("MoonberryCategory.CalcScore() = %d, want 50", got)
+	}
+
+	if got, _ := gs.Categories.FiveCategory.CalcScore(berries); got != 100 {
+		t.Errorf("FiveCategory.CalcScore() = %d, want 100", got)
+	}
+
+	if got, _ := gs.Categories.FreeCategory.CalcScore(berries); got != 50 {
+		t.Errorf("FreeCategory.CalcScore() = %d, want 50", got)
+	}
+}
@@ -3,7 +3,12 @@ package game
 import "fmt"
 
 func (gs *GameState) ScoreCategory(cat Category) error {
-	if gs.RollsLeftInTurn > 2 {
+	return gs.scoreCategory(cat, true)
+}
+
+// scoreCategory scores the category and starts the next turn, rolling its jars if rollNext is true.
+func (gs *GameState) scoreCategory(cat Category, rollNext bool) error {
+	if gs.RollsLeftInTurn >= gs.ruleSet().RollsPerTurn {
 		return fmt.Errorf("must have rolled once to score category")
 	}
 
@@ -13,8 +18,31 @@ func (gs *GameState) ScoreCategory(cat Category) error {
 	}
 
 	gs.Score += score
+	for id, c := range gs.Categories.All() {
+		if c == cat {
+			gs.record(Event{Kind: EventScore, Category: CategoryID(id), Points: score})
+		}
+	}
+
 	gs.NewTurn()
-	gs.RollJars()
+	if rollNext {
+		gs.RollJars()
+	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+// ScoreOptions returns the score each open category would get with the current berries.
+// The game state isn't changed.
+func (gs *GameState) ScoreOptions() map[Category]int {
+	berries := gs.GetBerries()
+	options := make(map[Category]int)
+
+	for _, cat := range gs.Categories.All() {
+		if !cat.IsUsed() {
+			options[cat] = cat.PreviewScore(berries)
+		}
+	}
+
+	return options
+}
@@ -0,0 +1,163 @@
+package genome
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"gorgonia.org/gorgonia"
+)
+
+// leakySlope is the slope of ActLeakyReLU for negative inputs.
+const leakySlope = 0.01
+
+// An Activation names the function applied to the outputs of a layer.
+// Every genome saves one per layer, from the first hidden layer to the output layer.
+type Activation string
+
+const (
+	// ActReLU zeroes negative values. It is the activation of the hidden layers by default.
+	ActReLU Activation = "relu"
+
+	// ActLeakyReLU scales negative values down instead of zeroing them, so their units keep changing when mutated.
+	ActLeakyReLU Activation = "leakyrelu"
+
+	// ActTanh squashes values into (-1, 1).
+	ActTanh Activation = "tanh"
+
+	// ActSigmoid squashes values into (0, 1).
+	ActSigmoid Activation = "sigmoid"
+
+	// ActIdentity leaves values as they are. It is the activation of the output layer by default.
+	ActIdentity Activation = "identity"
+
+	// ActSoftmax turns the category and re-roll outputs into probabilities that add up to 1.
+	// The jar lock outputs are left as they are, since every jar is locked on its own.
+	// It can only be used on the output layer.
+	ActSoftmax Activation = "softmax"
+)
+
+// DefaultActivations returns the activations networks had before they were configurable:
+// ReLU for each hidden layer and identity for the output layer.
+func DefaultActivations(hiddenLayers int) []Activation {
+	acts := make([]Activation, hiddenLayers+1)
+	for i := range hiddenLayers {
+		acts[i] = ActReLU
+	}
+	acts[hiddenLayers] = ActIdentity
+
+	return acts
+}
+
+// CheckActivations returns an error unless there is a known activation for each of the layers,
+// with softmax only on the output layer.
+func CheckActivations(acts []Activation, layers int) error {
+	if len(acts) != layers {
+		return fmt.Errorf("expected %d activations, one per layer, got %d", layers, len(acts))
+	}
+
+	for i, act := range acts {
+		switch act {
+		case ActReLU, ActLeakyReLU, ActTanh, ActSigmoid, ActIdentity:
+		case ActSoftmax:
+			if i != layers-1 {
+				return fmt.Errorf("softmax can only be used on the output layer, not layer %d", i)
+			}
+		default:
+			return fmt.Errorf("unknown activation %q", act)
+		}
+	}
+
+	return nil
+}
+
+// initWeight returns a random initial weight for a layer with the activation,
+// scaled to the number of inputs and outputs of the layer so its outputs neither vanish nor explode.
+// ReLU layers use He initialization, the others Glorot initialization.
+func (a Activation) initWeight(rng *rand.Rand, fanIn, fanOut int) float64 {
+	switch a {
+	case ActReLU, ActLeakyReLU:
+		return he(rng, fanIn)
+	default:
+		return glorot(rng, fanIn, fanOut)
+	}
+}
+
+// apply applies the activation to the outputs of a layer in place.
+func (a Activation) apply(z []float64) {
+	switch a {
+	case ActReLU:
+		for i, v := range z {
+			if v < 0 {
+				z[i] = 0
+			}
+		}
+	case ActLeakyReLU:
+		for i, v := range z {
+			if v < 0 {
+				z[i] = v * leakySlope
+			}
+		}
+	case ActTanh:
+		for i, v := range z {
+			z[i] = math.Tanh(v)
+		}
+	case ActSigmoid:
+		for i, v := range z {
+			z[i] = 1 / (1 + math.Exp(-v))
+		}
+	case ActSoftmax:
+		softmax(z[firstCategoryOutput:])
+	}
+}
+
+// softmax replaces the values with their softmax, in place.
+func softmax(z []float64) {
+	// subtracting the highest value keeps the exponentials from overflowing
+	highest := math.Inf(-1)
+	for _, v := range z {
+		highest = max(highest, v)
+	}
+
+	total := 0.0
+	for i, v := range z {
+		z[i] = math.Exp(v - highest)
+		total += z[i]
+	}
+
+	for i := range z {
+		z[i] /= total
+	}
+}
+
+// node applies the activation to the outputs of a layer in a graph built by BuildGraph.
+func (a Activation) node(z *gorgonia.Node) (*gorgonia.Node, error) {
+	switch a {
+	case ActReLU:
+		return gorgonia.Rectify(z)
+	case ActLeakyReLU:
+		return gorgonia.LeakyRelu(z, leakySlope)
+	case ActTanh:
+		return gorgonia.Tanh(z)
+	case ActSigmoid:
+		return gorgonia.Sigmoid(z)
+	case ActSoftmax:
+		locks, err := gorgonia.Slice(z, nil, gorgonia.S(0, firstCategoryOutput))
+		if err != nil {
+			return nil, err
+		}
+
+		actions, err := gorgonia.Slice(z, nil, gorgonia.S(firstCategoryOutput, OutputSize))
+		if err != nil {
+			return nil, err
+		}
+
+		if actions, err = gorgonia.SoftMax(actions); err != nil {
+			return nil, err
+		}
+
+		return gorgonia.Concat(1, locks, actions)
+	default:
+		return z, nil
+	}
+}
@@ -0,0 +1,150 @@
+package genome
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestCheckActivations(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		acts    []Activation
+		layers  int
+		wantErr bool
+	}{
+		{name: "Defaults", acts: DefaultActivations(2), layers: 3},
+		{name: "Softmax output", acts: []Activation{ActTanh, ActLeakyReLU, ActSoftmax}, layers: 3},
+		{name: "No hidden layers", acts: []Activation{ActSigmoid}, layers: 1},
+		{name: "Too few", acts: []Activation{ActReLU}, layers: 2, wantErr: true},
+		{name: "Unknown", acts: []Activation{ActReLU, "swish"}, layers: 2, wantErr: true},
+		{name: "Hidden softmax", acts: []Activation{ActSoftmax, ActIdentity}, layers: 2, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if err := CheckActivations(tt.acts, tt.layers); (err != nil) != tt.wantErr {
+				t.Errorf("CheckActivations() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestActivations_Forward checks that a Network applies every activation the same way as the graph.
+func TestActivations_Forward(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		acts []Activation
+	}{
+		{name: "leaky relu", acts: []Activation{ActLeakyReLU, ActLeakyReLU, ActIdentity}},
+		{name: "tanh", acts: []Activation{ActTanh, ActTanh, ActTanh}},
+		{name: "sigmoid", acts: []Activation{ActSigmoid, ActReLU, ActSigmoid}},
+		{name: "softmax", acts: []Activation{ActReLU, ActTanh, ActSoftmax}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			rng := rand.New(rand.NewSource(1))
+			g := NewGenome(rng, []int{8, 4}, nil, tt.acts...)
+
+			n, err := NewNetwork(g)
+			if err != nil {
+				t.Fatalf("NewNetwork() error = %v", err)
+			}
+
+			for range 5 {
+				inputs := make([]float64, InputSize)
+				for i := range inputs {
+					inputs[i] = rng.NormFloat64()
+				}
+
+				want := graphOutputs(t, g, inputs)
+				got := n.Forward(inputs)
+
+				for i := range want {
+					if math.Abs(got[i]-want[i]) > 1e-9 {
+						t.Fatalf("Forward()[%d] = %v, want %v", i, got[i], want[i])
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestActSoftmax(t *testing.T) {
+	t.Parallel()
+	z := []float64{-1, 2, 0, 5, -3, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	locks := append([]float64{}, z[:firstCategoryOutput]...)
+	ActSoftmax.apply(z)
+
+	if !reflect.DeepEqual(z[:firstCategoryOutput], locks) {
+		t.Errorf("softmax changed the lock outputs to %v, want %v", z[:firstCategoryOutput], locks)
+	}
+
+	total := 0.0
+	for i := firstCategoryOutput; i < OutputSize; i++ {
+		total += z[i]
+		if i > firstCategoryOutput && z[i] <= z[i-1] {
+			t.Errorf("softmax output %d = %v, want more than output %d = %v", i, z[i], i-1, z[i-1])
+		}
+	}
+
+	if math.Abs(total-1) > 1e-12 {
+		t.Errorf("softmax outputs add up to %v, want 1", total)
+	}
+}
+
+func TestNewGenome_Init(t *testing.T) {
+	t.Parallel()
+	g := NewGenome(rand.New(rand.NewSource(0)), []int{64, 8}, nil, ActReLU, ActTanh, ActIdentity)
+
+	// He initialization for the ReLU layer, Glorot for the others, from each layer's own size
+	limits := []float64{
+		math.Sqrt(6.0 / InputSize),
+		math.Sqrt(6.0 / (64 + 8)),
+		math.Sqrt(6.0 / (8 + OutputSize)),
+	}
+
+	for layer, limit := range limits {
+		most := 0.0
+		for _, row := range g.Weights[layer] {
+			for _, w := range row {
+				most = max(most, math.Abs(w))
+			}
+		}
+
+		// the largest of many uniform draws is close to the limit
+		if most > limit || most < 0.9*limit {
+			t.Errorf("layer %d has weights up to %v, want up to %v", layer, most, limit)
+		}
+	}
+}
+
+func TestGenome_DefaultActivations(t *testing.T) {
+	t.Parallel()
+	g := NewGenome(rand.New(rand.NewSource(0)), []int{6}, nil)
+	if want := []Activation{ActReLU, ActIdentity}; !reflect.DeepEqual(g.Activations, want) {
+		t.Fatalf("NewGenome() activations = %v, want %v", g.Activations, want)
+	}
+
+	// genomes saved before activations were recorded use the defaults
+	old := g.Clone().(*Genome)
+	old.Activations = nil
+	if err := old.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	inputs := make([]float64, InputSize)
+	inputs[3], inputs[30] = 1, 1
+	if want, got := graphOutputs(t, g, inputs), graphOutputs(t, old, inputs); !reflect.DeepEqual(got, want) {
+		t.Errorf("genome without activations outputs %v, want %v", got, want)
+	}
+
+	old.Activations = []Activation{ActSoftmax, ActIdentity}
+	if err := old.Validate(); err == nil {
+		t.Errorf("Validate() expected an error for a hidden softmax layer")
+	}
+}
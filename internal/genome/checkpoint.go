@@ -0,0 +1,269 @@
+package genome
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+
+	"github.com/MaxHalford/eaopt"
+)
+
+// CheckpointVersion is the version of the checkpoint format written by SaveCheckpoint.
+const CheckpointVersion = 1
+
+// A SavedIndividual is a genome together with its fitness. It holds either a Genome or a NEATGenome.
+type SavedIndividual struct {
+	Genome  *Genome     `json:"genome,omitempty"`
+	NEAT    *NEATGenome `json:"neat,omitempty"`
+	Fitness float64     `json:"fitness"`
+}
+
+// genome returns the saved genome, whichever kind it is.
+func (s SavedIndividual) genome() eaopt.Genome {
+	if s.NEAT != nil {
+		return s.NEAT
+	}
+	return s.Genome
+}
+
+// A Checkpoint holds everything needed to resume training: every population, the hall of fame,
+// the generation counter and the state of the random number generators.
+type Checkpoint struct {
+	Version    int            `json:"version"`
+	Config     TrainingConfig `json:"config"`
+	Generation uint           `json:"generation"`
+
+	// Math/rand generators can't be serialized, so when a checkpoint is taken every generator is reseeded
+	// with a seed drawn from itself, and the seeds are stored instead.
+	Seed     int64   `json:"seed"`
+	PopSeeds []int64 `json:"popSeeds"`
+
+	// EvalSeed reseeds the Evaluation, so a resumed run plays the same games as the original. Older checkpoints
+	// don't have it, and resume with the games drawn from Seed.
+	EvalSeed int64 `json:"evalSeed,omitempty"`
+
+	Populations [][]SavedIndividual `json:"populations"`
+	HallOfFame  []SavedIndividual   `json:"hallOfFame"`
+
+	// Innovations and Species are only saved when training NEAT genomes. Innovations is shared by every NEAT
+	// genome of the checkpoint once it is loaded, and Species holds the representatives of the NEATModel.
+	Innovations *Innovations  `json:"innovations,omitempty"`
+	Species     []*NEATGenome `json:"species,omitempty"`
+}
+
+// SaveCheckpoint writes a checkpoint of the GA and the evaluation its genomes share to the JSON file at path.
+// The random number generators of the GA and the evaluation are reseeded so that the run continues exactly like
+// a resumed one would. eval may be nil if the genomes have no evaluation.
+func SaveCheckpoint(path string, ga *eaopt.GA, config TrainingConfig, eval *Evaluation) error {
+	c := Checkpoint{
+		Version:    CheckpointVersion,
+		Config:     config,
+		Generation: ga.Generations,
+		Seed:       ga.RNG.Int63(),
+	}
+	ga.RNG.Seed(c.Seed)
+
+	if eval != nil {
+		c.EvalSeed = eval.rng.Int63()
+		eval.rng.Seed(c.EvalSeed)
+	}
+
+	for _, pop := range ga.Populations {
+		seed := pop.RNG.Int63()
+		pop.RNG.Seed(seed)
+		c.PopSeeds = append(c.PopSeeds, seed)
+
+		individuals, err := saveIndividuals(pop.Individuals)
+		if err != nil {
+			return err
+		}
+		c.Populations = append(c.Populations, individuals)
+	}
+
+	var err error
+	if c.HallOfFame, err = saveIndividuals(ga.HallOfFame); err != nil {
+		return err
+	}
+
+	for _, indi := range c.Populations[0] {
+		if indi.NEAT != nil && indi.NEAT.Innovations != nil {
+			c.Innovations = indi.NEAT.Innovations
+			break
+		}
+	}
+
+	if m, ok := ga.Model.(*NEATModel); ok {
+		c.Species = m.representatives
+	}
+
+	data, err := json.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("error encoding checkpoint: %w", err)
+	}
+
+	return writeFileAtomic(path, data)
+}
+
+// saveIndividuals converts evaluated individuals to SavedIndividuals, skipping empty hall of fame slots.
+func saveIndividuals(indis eaopt.Individuals) ([]SavedIndividual, error) {
+	var saved []SavedIndividual
+	for _, indi := range indis {
+		if indi.Genome == nil {
+			continue
+		}
+
+		switch g := indi.Genome.(type) {
+		case *Genome:
+			saved = append(saved, SavedIndividual{Genome: g, Fitness: indi.Fitness})
+		case *NEATGenome:
+			saved = append(saved, SavedIndividual{NEAT: g, Fitness: indi.Fitness})
+		default:
+			return nil, fmt.Errorf("can't checkpoint genome of type %T", indi.Genome)
+		}
+	}
+
+	return saved, nil
+}
+
+// LoadCheckpoint reads a checkpoint saved with SaveCheckpoint from path.
+func LoadCheckpoint(path string) (*Checkpoint, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading checkpoint: %w", err)
+	}
+
+	// config fields missing from older checkpoints keep their default
+	c := Checkpoint{Config: DefaultTrainingConfig()}
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("error decoding checkpoint: %w", err)
+	}
+
+	if c.Version != CheckpointVersion {
+		return nil, fmt.Errorf("unsupported checkpoint version %d", c.Version)
+	}
+
+	if len(c.Populations) == 0 || len(c.PopSeeds) != len(c.Populations) {
+		return nil, fmt.Errorf("checkpoint has %d populations and %d seeds", len(c.Populations), len(c.PopSeeds))
+	}
+
+	individuals := append([]SavedIndividual{}, c.HallOfFame...)
+	for _, pop := range c.Populations {
+		if len(pop) == 0 {
+			return nil, fmt.Errorf("checkpoint has an empty population")
+		}
+		individuals = append(individuals, pop...)
+	}
+
+	neat := append([]*NEATGenome{}, c.Species...)
+	for _, indi := range individuals {
+		switch {
+		case indi.Genome == nil && indi.NEAT == nil:
+			return nil, fmt.Errorf("checkpoint has an individual without a genome")
+		case indi.Genome != nil && indi.NEAT != nil:
+			return nil, fmt.Errorf("checkpoint has an individual with both a genome and a NEAT genome")
+		case indi.Genome != nil:
+			if err := indi.Genome.Validate(); err != nil {
+				return nil, err
+			}
+		default:
+			neat = append(neat, indi.NEAT)
+		}
+	}
+
+	if len(neat) > 0 && c.Innovations == nil {
+		return nil, fmt.Errorf("checkpoint has NEAT genomes but no innovations")
+	}
+
+	for _, g := range neat {
+		if g == nil {
+			return nil, fmt.Errorf("checkpoint has a species without a representative")
+		}
+
+		if err := g.Validate(); err != nil {
+			return nil, err
+		}
+		g.Innovations = c.Innovations
+	}
+
+	return &c, nil
+}
+
+// Factory returns a GenomeFactory that hands out copies of the saved individuals, population by population,
+// instead of randomly initialized genomes. It starts over from the first individual once they run out.
+func (c *Checkpoint) Factory() GenomeFactory {
+	var genomes []eaopt.Genome
+	for _, pop := range c.Populations {
+		for _, indi := range pop {
+			genomes = append(genomes, indi.genome())
+		}
+	}
+
+	next := 0
+	return func(rng *rand.Rand) eaopt.Genome {
+		g := genomes[next%len(genomes)]
+		next++
+		return g.Clone()
+	}
+}
+
+// Restore applies the populations, generation counter, hall of fame, random number generator state and, if the GA
+// breeds with a NEATModel, the species of the checkpoint to a GA that has just been initialized with the
+// checkpoint's Factory, and reseeds eval if it isn't nil.
+//
+// The individuals are put back in their saved order with their saved fitness, which the next generation is
+// selected from, since re-evaluating them on other games sorts them differently. Each keeps the evaluation that
+// the factory gave the genome in its place.
+func (c *Checkpoint) Restore(ga *eaopt.GA, eval *Evaluation) {
+	for i := range ga.Populations {
+		if i >= len(c.Populations) {
+			break
+		}
+
+		indis := ga.Populations[i].Individuals
+		for j := range indis {
+			if j >= len(c.Populations[i]) {
+				break
+			}
+
+			saved := c.Populations[i][j]
+			g := saved.genome().Clone()
+			setEvaluation(g, evaluationOf(indis[j].Genome))
+			if neat, ok := g.(*NEATGenome); ok {
+				neat.fitness, neat.evaluated = saved.Fitness, true
+			}
+			indis[j].Genome, indis[j].Fitness, indis[j].Evaluated = g, saved.Fitness, true
+		}
+	}
+
+	ga.Generations = c.Generation
+	ga.RNG.Seed(c.Seed)
+
+	for i := range ga.Populations {
+		if i < len(c.PopSeeds) {
+			ga.Populations[i].RNG.Seed(c.PopSeeds[i])
+		}
+	}
+
+	if eval != nil && c.EvalSeed != 0 {
+		eval.rng.Seed(c.EvalSeed)
+	}
+
+	// the saved hall of fame already holds the best of the saved populations
+	for i := range ga.HallOfFame {
+		if i < len(c.HallOfFame) {
+			saved := c.HallOfFame[i]
+			ga.HallOfFame[i] = eaopt.Individual{Genome: saved.genome().Clone(), Fitness: saved.Fitness, Evaluated: true}
+		} else {
+			ga.HallOfFame[i] = eaopt.Individual{Fitness: math.Inf(1)}
+		}
+	}
+
+	if m, ok := ga.Model.(*NEATModel); ok {
+		m.representatives = nil
+		for _, rep := range c.Species {
+			m.representatives = append(m.representatives, rep.Clone().(*NEATGenome))
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package genome
+
+import (
+	"math/rand"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/MaxHalford/eaopt"
+)
+
+// newTestGA returns a small GA for testing checkpoints.
+func newTestGA(t *testing.T, factory GenomeFactory) *eaopt.GA {
+	t.Helper()
+	return newModelTestGA(t, factory, nil)
+}
+
+// newModelTestGA returns a small GA like newTestGA that breeds with model, or the default model if it is nil.
+func newModelTestGA(t *testing.T, factory GenomeFactory, model eaopt.Model) *eaopt.GA {
+	t.Helper()
+	config := eaopt.NewDefaultGAConfig()
+	config.NGenerations = 2
+	config.PopSize = 4
+	config.HofSize = 2
+	config.RNG = rand.New(rand.NewSource(0))
+	if model != nil {
+		config.Model = model
+	}
+
+	ga, err := config.NewGA()
+	if err != nil {
+		t.Fatalf("NewGA() error = %v", err)
+	}
+
+	if err := ga.Minimize(factory); err != nil {
+		t.Fatalf("Minimize() error = %v", err)
+	}
+
+	return ga
+}
+
+func TestCheckpoint_RoundTrip(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+	config := TrainingConfig{Generations: 2, PopSize: 4, HiddenLayerSizes: []int{3}}
+	ga := newTestGA(t, NewGenomeFactory(config.HiddenLayerSizes, nil))
+
+	if err := SaveCheckpoint(path, ga, config, nil); err != nil {
+		t.Fatalf("SaveCheckpoint() error = %v", err)
+	}
+
+	checkpoint, err := LoadCheckpoint(path)
+	if err != nil {
+		t.Fatalf("LoadCheckpoint() error = %v", err)
+	}
+
+	if checkpoint.Generation != ga.Generations {
+		t.Errorf("expected generation %d, got %d", ga.Generations, checkpoint.Generation)
+	}
+
+	if !reflect.DeepEqual(checkpoint.Config, config) {
+		t.Errorf("expected config %+v, got %+v", config, checkpoint.Config)
+	}
+
+	// the factory should hand out the saved population in order
+	factory := checkpoint.Factory()
+	for i, indi := range ga.Populations[0].Individuals {
+		if got := factory(nil); !reflect.DeepEqual(got, indi.Genome) {
+			t.Errorf("factory genome %d does not match the checkpointed population", i)
+		}
+	}
+
+	if len(checkpoint.HallOfFame) != 2 || checkpoint.HallOfFame[0].Fitness != ga.HallOfFame[0].Fitness {
+		t.Errorf("hall of fame was not saved correctly")
+	}
+}
+
+func TestCheckpoint_Restore(t *testing.T) {
+	t.Parallel()
+	best := NewGenome(rand.New(rand.NewSource(1)), []int{3}, nil)
+	checkpoint := &Checkpoint{
+		Generation: 40,
+		Seed:       7,
+		PopSeeds:   []int64{8},
+		Populations: [][]SavedIndividual{
+			{{Genome: NewGenome(rand.New(rand.NewSource(2)), []int{3}, nil), Fitness: 200}},
+		},
+		HallOfFame: []SavedIndividual{{Genome: best, Fitness: -1000}},
+	}
+
+	ga := newTestGA(t, checkpoint.Factory())
+	checkpoint.Restore(ga, nil)
+
+	if ga.Generations != 40 {
+		t.Errorf("expected generation 40, got %d", ga.Generations)
+	}
+
+	if ga.HallOfFame[0].Fitness != -1000 || !reflect.DeepEqual(ga.HallOfFame[0].Genome, best) {
+		t.Errorf("saved hall of fame was not restored")
+	}
+
+	if want := rand.New(rand.NewSource(7)).Int63(); ga.RNG.Int63() != want {
+		t.Errorf("GA random number generator was not restored")
+	}
+}
+
+func TestCheckpoint_RestoreEvaluation(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+	config := TrainingConfig{Generations: 2, PopSize: 4, HiddenLayerSizes: []int{3}}
+	ga := newTestGA(t, NewGenomeFactory(config.HiddenLayerSizes, nil))
+
+	eval, err := NewEvaluation(3, StatMean, 1)
+	if err != nil {
+		t.Fatalf("NewEvaluation() error = %v", err)
+	}
+
+	if err := SaveCheckpoint(path, ga, config, eval); err != nil {
+		t.Fatalf("SaveCheckpoint() error = %v", err)
+	}
+	if err := eval.NextGeneration(ga); err != nil {
+		t.Fatalf("NextGeneration() error = %v", err)
+	}
+
+	checkpoint, err := LoadCheckpoint(path)
+	if err != nil {
+		t.Fatalf("LoadCheckpoint() error = %v", err)
+	}
+
+	resumed, err := NewEvaluation(3, StatMean, 2)
+	if err != nil {
+		t.Fatalf("NewEvaluation() error = %v", err)
+	}
+
+	restored := newTestGA(t, checkpoint.Factory().WithEvaluation(resumed))
+	checkpoint.Restore(restored, resumed)
+	if err := resumed.NextGeneration(restored); err != nil {
+		t.Fatalf("NextGeneration() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(resumed.Seeds(), eval.Seeds()) {
+		t.Errorf("resumed evaluation plays seeds %v, want %v", resumed.Seeds(), eval.Seeds())
+	}
+
+	// the individuals are back in their saved order with their saved fitness, and keep the new evaluation
+	for i, indi := range restored.Populations[0].Individuals {
+		want := ga.Populations[0].Individuals[i]
+		g := indi.Genome.(*Genome)
+		if indi.Fitness != want.Fitness || !reflect.DeepEqual(g.Weights, want.Genome.(*Genome).Weights) {
+			t.Errorf("individual %d was not restored", i)
+		}
+		if g.Eval != resumed {
+			t.Errorf("individual %d lost its evaluation", i)
+		}
+	}
+}
+
+func TestCheckpoint_RestoreNEAT(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+	config := TrainingConfig{Generations: 2, PopSize: 4, Kind: KindNEAT}
+	innovations := NewInnovations(InputSize)
+	model := &NEATModel{Threshold: 3, CrossRate: 0.5, NContestants: 2}
+	ga := newModelTestGA(t, NewNEATFactory(nil, innovations), model)
+
+	// number a split, so the innovations have moved on from those of a new population
+	g := ga.Populations[0].Individuals[0].Genome.(*NEATGenome)
+	split := innovations.Split(g, 3)
+
+	if err := SaveCheckpoint(path, ga, config, nil); err != nil {
+		t.Fatalf("SaveCheckpoint() error = %v", err)
+	}
+
+	checkpoint, err := LoadCheckpoint(path)
+	if err != nil {
+		t.Fatalf("LoadCheckpoint() error = %v", err)
+	}
+
+	restoredModel := &NEATModel{Threshold: 3, CrossRate: 0.5, NContestants: 2}
+	restored := newModelTestGA(t, checkpoint.Factory(), restoredModel)
+	checkpoint.Restore(restored, nil)
+
+	// every genome shares the restored innovations
+	restoredInnovations := restored.Populations[0].Individuals[0].Genome.(*NEATGenome).Innovations
+	for i, indi := range restored.Populations[0].Individuals {
+		got := indi.Genome.(*NEATGenome)
+		if got.Innovations != restoredInnovations || got.Innovations == innovations {
+			t.Errorf("individual %d doesn't share the restored innovations", i)
+		}
+		if want := ga.Populations[0].Individuals[i].Genome.(*NEATGenome); !reflect.DeepEqual(got.Conns, want.Conns) {
+			t.Errorf("individual %d was not restored", i)
+		}
+	}
+
+	// and they carry on numbering where the saved ones stopped
+	if got := restoredInnovations.Split(g, 3); got != split {
+		t.Errorf("Split() = %d after restoring, want %d", got, split)
+	}
+	if got, want := restoredInnovations.Split(g, 4), innovations.Split(g, 4); got != want {
+		t.Errorf("Split() = %d for a new split after restoring, want %d", got, want)
+	}
+	if got, want := restoredInnovations.Conn(split, 0), innovations.Conn(split, 0); got != want {
+		t.Errorf("Conn() = %d for a new connection after restoring, want %d", got, want)
+	}
+
+	if len(model.representatives) == 0 || len(restoredModel.representatives) != len(model.representatives) {
+		t.Fatalf("restored %d species, want %d", len(restoredModel.representatives), len(model.representatives))
+	}
+	for i, rep := range restoredModel.representatives {
+		if !reflect.DeepEqual(rep.Conns, model.representatives[i].Conns) {
+			t.Errorf("species %d has a different representative", i)
+		}
+	}
+}
@@ -0,0 +1,241 @@
+package genome
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"math/rand"
+	"slices"
+
+	"github.com/iadams749/JumbleBerryFieldsBot/internal/game"
+)
+
+const (
+	// firstCategoryOutput is the output of the first category, the categories follow in scorecard order.
+	firstCategoryOutput = 5
+
+	// rerollOutput is the output of the re-roll action.
+	rerollOutput = 14
+
+	// decoderSeed seeds the generator of every sampling decoder, so a network plays the same moves on the same dice
+	// and evaluations stay reproducible.
+	decoderSeed = 1
+)
+
+// A Decoding names the way network outputs are turned into actions.
+// It is saved with every genome, since a network is trained to pick its moves a certain way.
+type Decoding string
+
+const (
+	// DecodingArgmax takes the legal action with the highest output, and locks the jars with a positive output.
+	DecodingArgmax Decoding = "argmax"
+
+	// DecodingSigmoid takes the legal action with the highest output like DecodingArgmax,
+	// but locks each jar with a probability of the sigmoid of its output.
+	DecodingSigmoid Decoding = "sigmoid"
+
+	// DecodingSoftmax samples the action from the softmax of the outputs of the legal actions, for exploration.
+	// It locks the jars like DecodingArgmax.
+	DecodingSoftmax Decoding = "softmax"
+)
+
+// NewDecoder returns a new Decoder for the decoding. An empty decoding is DecodingArgmax, which genomes
+// saved before decodings existed were trained with.
+func (d Decoding) NewDecoder() (Decoder, error) {
+	switch d {
+	case DecodingArgmax, "":
+		return ArgmaxDecoder{}, nil
+	case DecodingSigmoid:
+		return &SigmoidDecoder{rng: rand.New(rand.NewSource(decoderSeed))}, nil
+	case DecodingSoftmax:
+		return &SoftmaxDecoder{rng: rand.New(rand.NewSource(decoderSeed))}, nil
+	default:
+		return nil, fmt.Errorf("unknown decoding %q", d)
+	}
+}
+
+// A Decoder turns the outputs of a network into an action.
+// Outputs 0-4 are the jar locks, 5-13 are the categories in scorecard order and 14 is the re-roll.
+//
+// Illegal actions are masked out, so a decoder always returns a legal action unless the game is over.
+type Decoder interface {
+	// Decoding returns the name of the decoding, which is saved with genomes.
+	Decoding() Decoding
+
+	// Decode returns the action for the outputs, along with every illegal action the network preferred over it.
+	Decode(gs *game.GameState, outputs []float64) (game.Action, []SkippedAction, error)
+}
+
+// ArgmaxDecoder is the default Decoder. It takes the legal action with the highest output,
+// and re-rolls the jars with a lock output that isn't positive.
+type ArgmaxDecoder struct{}
+
+// Decoding returns DecodingArgmax.
+func (ArgmaxDecoder) Decoding() Decoding {
+	return DecodingArgmax
+}
+
+// Decode returns the legal action with the highest output.
+func (ArgmaxDecoder) Decode(gs *game.GameState, outputs []float64) (game.Action, []SkippedAction, error) {
+	return decode(gs, outputs, positiveLocks(outputs), argmax)
+}
+
+// A SigmoidDecoder takes the legal action with the highest output, and locks each jar with the probability
+// of the sigmoid of its lock output, so a jar with an output of 0 is locked half of the time.
+//
+// It holds its own random number generator, so it must not be used from more than one goroutine at a time.
+type SigmoidDecoder struct {
+	rng *rand.Rand
+}
+
+// Decoding returns DecodingSigmoid.
+func (*SigmoidDecoder) Decoding() Decoding {
+	return DecodingSigmoid
+}
+
+// Decode returns the legal action with the highest output, with the jars locked at random.
+func (s *SigmoidDecoder) Decode(gs *game.GameState, outputs []float64) (game.Action, []SkippedAction, error) {
+	if len(outputs) != OutputSize {
+		return game.Action{}, nil, fmt.Errorf("expected %d outputs, got %d", OutputSize, len(outputs))
+	}
+
+	var keep uint
+	for jar := range firstCategoryOutput {
+		if s.rng.Float64() < 1/(1+math.Exp(-outputs[jar])) {
+			keep |= 1 << jar
+		}
+	}
+
+	return decode(gs, outputs, keep, argmax)
+}
+
+// A SoftmaxDecoder samples the action from the softmax of the outputs of the legal actions,
+// and re-rolls the jars with a lock output that isn't positive.
+//
+// It holds its own random number generator, so it must not be used from more than one goroutine at a time.
+type SoftmaxDecoder struct {
+	rng *rand.Rand
+}
+
+// Decoding returns DecodingSoftmax.
+func (*SoftmaxDecoder) Decoding() Decoding {
+	return DecodingSoftmax
+}
+
+// Decode samples a legal action, preferring the ones with higher outputs.
+func (s *SoftmaxDecoder) Decode(gs *game.GameState, outputs []float64) (game.Action, []SkippedAction, error) {
+	return decode(gs, outputs, positiveLocks(outputs), s.sample)
+}
+
+// sample returns one of the legal outputs with a probability of its softmax.
+func (s *SoftmaxDecoder) sample(outputs []float64, legal outputMask) int {
+	// subtracting the highest output keeps the exponentials from overflowing
+	highest := outputs[argmax(outputs, legal)]
+
+	total := 0.0
+	for i := firstCategoryOutput; i < OutputSize; i++ {
+		if legal.has(i) {
+			total += math.Exp(outputs[i] - highest)
+		}
+	}
+
+	last := 0
+	r := s.rng.Float64() * total
+	for i := firstCategoryOutput; i < OutputSize; i++ {
+		if !legal.has(i) {
+			continue
+		}
+
+		if r -= math.Exp(outputs[i] - highest); r < 0 {
+			return i
+		}
+		last = i
+	}
+
+	return last
+}
+
+// positiveLocks returns the keep mask of the jars with a positive lock output.
+func positiveLocks(outputs []float64) uint {
+	var keep uint
+	for jar := range firstCategoryOutput {
+		if outputs[jar] > 0 {
+			keep |= 1 << jar
+		}
+	}
+
+	return keep
+}
+
+// An outputMask has the bit of every legal category and re-roll output set.
+// Choosers take a mask instead of a slice of outputs, so the legal outputs don't escape to the heap on every move.
+type outputMask uint
+
+// has returns true if output i is in the mask.
+func (m outputMask) has(i int) bool {
+	return m&(1<<i) != 0
+}
+
+// argmax returns the legal output with the highest value, which must not be called with an empty mask.
+func argmax(outputs []float64, legal outputMask) int {
+	best := -1
+	for i := firstCategoryOutput; i < OutputSize; i++ {
+		if legal.has(i) && (best < 0 || outputs[i] > outputs[best]) {
+			best = i
+		}
+	}
+
+	return best
+}
+
+// outputAction returns the action of a category or re-roll output, keeping the jars in keep if it re-rolls.
+func outputAction(i int, keep uint) game.Action {
+	if i == rerollOutput {
+		return game.Action{Kind: game.Reroll, Keep: keep}
+	}
+
+	return game.NewScore(game.CategoryID(i - firstCategoryOutput))
+}
+
+// decode masks out the illegal actions and lets choose pick one of the legal category and re-roll outputs.
+// It also returns every illegal action with a higher output than the chosen one, from the highest output down.
+// It only allocates when it skips illegal actions, since it runs on every move.
+func decode(gs *game.GameState, outputs []float64, keep uint, choose func(outputs []float64, legal outputMask) int) (game.Action, []SkippedAction, error) {
+	if len(outputs) != OutputSize {
+		return game.Action{}, nil, fmt.Errorf("expected %d outputs, got %d", OutputSize, len(outputs))
+	}
+
+	var legal outputMask
+	var illegalBuf [OutputSize - firstCategoryOutput]int
+	illegal := illegalBuf[:0]
+
+	for i := firstCategoryOutput; i < OutputSize; i++ {
+		if gs.IsLegal(outputAction(i, keep)) {
+			legal |= 1 << i
+		} else {
+			illegal = append(illegal, i)
+		}
+	}
+
+	chosen := -1
+	if legal != 0 {
+		chosen = choose(outputs, legal)
+	}
+
+	var skipped []SkippedAction
+	slices.SortStableFunc(illegal, func(a, b int) int { return cmp.Compare(outputs[b], outputs[a]) })
+	for _, i := range illegal {
+		if chosen >= 0 && outputs[i] <= outputs[chosen] {
+			break
+		}
+
+		action := outputAction(i, keep)
+		skipped = append(skipped, SkippedAction{Action: action, Reason: gs.CheckAction(action)})
+	}
+
+	if chosen < 0 {
+		return game.Action{}, skipped, fmt.Errorf("no legal action")
+	}
+
+	return outputAction(chosen, keep), skipped, nil
+}
@@ -0,0 +1,244 @@
+package genome
+
+import (
+	"math/rand"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/iadams749/JumbleBerryFieldsBot/internal/game"
+)
+
+func TestDecoding_NewDecoder(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		decoding Decoding
+		want     Decoding
+	}{
+		{decoding: "", want: DecodingArgmax},
+		{decoding: DecodingArgmax, want: DecodingArgmax},
+		{decoding: DecodingSigmoid, want: DecodingSigmoid},
+		{decoding: DecodingSoftmax, want: DecodingSoftmax},
+	}
+	for _, tt := range tests {
+		dec, err := tt.decoding.NewDecoder()
+		if err != nil {
+			t.Fatalf("NewDecoder(%q) error = %v", tt.decoding, err)
+		}
+
+		if dec.Decoding() != tt.want {
+			t.Errorf("NewDecoder(%q) = %s, want %s", tt.decoding, dec.Decoding(), tt.want)
+		}
+	}
+
+	if _, err := Decoding("beam").NewDecoder(); err == nil {
+		t.Errorf("NewDecoder() expected an error for an unknown decoding")
+	}
+}
+
+func TestArgmaxDecoder_Decode(t *testing.T) {
+	t.Parallel()
+	berries, err := game.ParseBerries("M M J S X")
+	if err != nil {
+		t.Fatalf("ParseBerries() error = %v", err)
+	}
+
+	// Free is used, and has the highest output
+	outputs := make([]float64, OutputSize)
+	copy(outputs, []float64{1, -1, 1, -1, 0})
+	outputs[firstCategoryOutput+int(game.CatFree)] = 5
+	outputs[rerollOutput] = 3
+	outputs[firstCategoryOutput+int(game.CatMixed)] = 2
+
+	tests := []struct {
+		name      string
+		rollsLeft int
+		want      game.Action
+		skipped   []game.Action
+	}{
+		{
+			name:      "Reroll",
+			rollsLeft: 1,
+			want:      game.NewReroll(0, 2),
+			skipped:   []game.Action{game.NewScore(game.CatFree)},
+		},
+		{
+			name:      "No rolls left",
+			rollsLeft: 0,
+			want:      game.NewScore(game.CatMixed),
+			skipped:   []game.Action{game.NewScore(game.CatFree), game.NewReroll(0, 2)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			gs, err := game.NewGameFromPosition(nil, game.Position{
+				Berries:   berries,
+				RollsLeft: tt.rollsLeft,
+				Used:      map[game.CategoryID]int{game.CatFree: 20},
+			})
+			if err != nil {
+				t.Fatalf("NewGameFromPosition() error = %v", err)
+			}
+
+			got, skipped, err := ArgmaxDecoder{}.Decode(gs, outputs)
+			if err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("Decode() = %v, want %v", got, tt.want)
+			}
+
+			var gotSkipped []game.Action
+			for _, s := range skipped {
+				gotSkipped = append(gotSkipped, s.Action)
+			}
+			if !reflect.DeepEqual(gotSkipped, tt.skipped) {
+				t.Errorf("Decode() skipped %v, want %v", gotSkipped, tt.skipped)
+			}
+		})
+	}
+}
+
+func TestDecoders_Legal(t *testing.T) {
+	t.Parallel()
+	for _, decoding := range []Decoding{DecodingArgmax, DecodingSigmoid, DecodingSoftmax} {
+		t.Run(string(decoding), func(t *testing.T) {
+			t.Parallel()
+			dec, err := decoding.NewDecoder()
+			if err != nil {
+				t.Fatalf("NewDecoder() error = %v", err)
+			}
+
+			rng := rand.New(rand.NewSource(3))
+			outputs := make([]float64, OutputSize)
+
+			for seed := range int64(20) {
+				gs := game.NewGameWithRoller(nil, game.NewSeededRoller(seed))
+				gs.RollJars()
+
+				// random outputs often prefer used categories, which must be masked out
+				for !gs.IsOver() {
+					for i := range outputs {
+						outputs[i] = rng.NormFloat64() * 3
+					}
+
+					action, _, err := dec.Decode(gs, outputs)
+					if err != nil {
+						t.Fatalf("Decode() error = %v", err)
+					}
+
+					if err := gs.Apply(action); err != nil {
+						t.Fatalf("Decode() returned an illegal action %v: %v", action, err)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestDecoders_Allocs(t *testing.T) {
+	// two categories are used, but the outputs prefer a legal one, so nothing is skipped
+	gs := game.NewGameWithRoller(nil, game.NewSeededRoller(0))
+	gs.RollJars()
+	for _, id := range []game.CategoryID{game.CatFree, game.CatMixed} {
+		if err := gs.Apply(game.NewScore(id)); err != nil {
+			t.Fatalf("Apply() error = %v", err)
+		}
+	}
+
+	outputs := make([]float64, OutputSize)
+	outputs[firstCategoryOutput+int(game.CatMoonberry)] = 1
+
+	for _, decoding := range []Decoding{DecodingArgmax, DecodingSigmoid, DecodingSoftmax} {
+		dec, err := decoding.NewDecoder()
+		if err != nil {
+			t.Fatalf("NewDecoder() error = %v", err)
+		}
+
+		allocs := testing.AllocsPerRun(100, func() {
+			if _, skipped, _ := dec.Decode(gs, outputs); len(skipped) > 0 {
+				t.Fatalf("%s Decode() skipped %v", decoding, skipped)
+			}
+		})
+		if allocs != 0 {
+			t.Errorf("%s Decode() allocates %v times per run, want 0", decoding, allocs)
+		}
+	}
+}
+
+func TestSoftmaxDecoder_Decode(t *testing.T) {
+	t.Parallel()
+	dec, err := DecodingSoftmax.NewDecoder()
+	if err != nil {
+		t.Fatalf("NewDecoder() error = %v", err)
+	}
+
+	gs := game.NewGameWithRoller(nil, game.NewSeededRoller(0))
+	gs.RollJars()
+
+	// Mixed Basket is strongly preferred, but the other actions are still explored
+	outputs := make([]float64, OutputSize)
+	outputs[firstCategoryOutput+int(game.CatMixed)] = 3
+
+	counts := map[game.Action]int{}
+	for range 1000 {
+		action, _, err := dec.Decode(gs, outputs)
+		if err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+		counts[action]++
+	}
+
+	if mixed := counts[game.NewScore(game.CatMixed)]; mixed < 600 || mixed > 800 {
+		t.Errorf("Decode() chose Mixed Basket %d times out of 1000, want about 700", mixed)
+	}
+
+	if len(counts) != OutputSize-firstCategoryOutput {
+		t.Errorf("Decode() chose %d different actions, want all %d", len(counts), OutputSize-firstCategoryOutput)
+	}
+}
+
+func TestDecoding_Genome(t *testing.T) {
+	t.Parallel()
+	factory := NewGenomeFactory([]int{6}, nil).WithDecoding(DecodingSigmoid)
+	g := factory(rand.New(rand.NewSource(4))).(*Genome)
+
+	if g.Clone().(*Genome).Decoding != DecodingSigmoid {
+		t.Errorf("Clone() did not copy the decoding")
+	}
+
+	path := filepath.Join(t.TempDir(), "genome.json")
+	if err := Save(path, g, TrainingInfo{}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	loaded, _, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if loaded.Decoding != DecodingSigmoid {
+		t.Fatalf("Load() decoding = %q, want %q", loaded.Decoding, DecodingSigmoid)
+	}
+
+	// the decoder is seeded, so every network of the genome plays the same moves on the same dice
+	var scores []int
+	for range 2 {
+		n, err := NewNetwork(loaded)
+		if err != nil {
+			t.Fatalf("NewNetwork() error = %v", err)
+		}
+		scores = append(scores, n.PlayGame(game.NewSeededRoller(5)))
+	}
+
+	if scores[0] != scores[1] {
+		t.Errorf("PlayGame() scores %v, want the same score twice", scores)
+	}
+
+	g.Decoding = "beam"
+	if err := g.Validate(); err == nil {
+		t.Errorf("Validate() expected an error for an unknown decoding")
+	}
+}
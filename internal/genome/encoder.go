@@ -0,0 +1,166 @@
+package genome
+
+import (
+	"fmt"
+
+	"github.com/iadams749/JumbleBerryFieldsBot/internal/game"
+)
+
+// An Encoding names the way game states are turned into network inputs.
+// It is saved with every genome, since a network only works with the inputs it was trained on.
+type Encoding string
+
+const (
+	// EncodingOneHot is the original encoding of InputSize inputs: the berries, rolls left and used categories, one-hot encoded.
+	EncodingOneHot Encoding = "onehot"
+
+	// EncodingRich adds berry counts, what every open category would score, the rounds left and the score to EncodingOneHot.
+	EncodingRich Encoding = "rich"
+)
+
+// NewEncoder returns a new Encoder for the encoding. An empty encoding is EncodingOneHot, which genomes
+// saved before encodings existed were trained with.
+func (e Encoding) NewEncoder() (Encoder, error) {
+	switch e {
+	case EncodingOneHot, "":
+		return OneHotEncoder{}, nil
+	case EncodingRich:
+		return &RichEncoder{}, nil
+	default:
+		return nil, fmt.Errorf("unknown encoding %q", e)
+	}
+}
+
+// An Encoder turns a game state into the inputs of a network.
+type Encoder interface {
+	// Encoding returns the name of the encoding, which is saved with genomes.
+	Encoding() Encoding
+
+	// Size returns the number of inputs the encoder writes.
+	Size() int
+
+	// Encode writes the inputs for the game state into dst, which must hold Size values.
+	// It is called on every move, so it shouldn't allocate.
+	Encode(gs *game.GameState, dst []float64)
+}
+
+// OneHotEncoder is the default Encoder. It writes InputSize inputs:
+// five for the berry in each jar, three for the rolls left and one for each used category.
+type OneHotEncoder struct{}
+
+// Encoding returns EncodingOneHot.
+func (OneHotEncoder) Encoding() Encoding {
+	return EncodingOneHot
+}
+
+// Size returns InputSize.
+func (OneHotEncoder) Size() int {
+	return InputSize
+}
+
+// Encode writes the one-hot inputs for the game state into dst.
+func (OneHotEncoder) Encode(gs *game.GameState, dst []float64) {
+	if len(gs.Jars) != 5 {
+		panic("invalid jar length")
+	}
+
+	clear(dst)
+
+	// making the berry inputs, one-hot encoded
+	for i, jar := range gs.Jars {
+		offset, ok := berryInputs[jar.Berry]
+		if !ok {
+			panic("unrecognized berry")
+		}
+		dst[i*5+offset] = 1.0
+	}
+
+	// encoding the round inputs
+	switch gs.RollsLeftInTurn {
+	case 2:
+		dst[25] = 1.0
+	case 1:
+		dst[26] = 1.0
+	case 0:
+		dst[27] = 1.0
+	default:
+		panic("must have rolled at least once")
+	}
+
+	// encoding the categories
+	for i, cat := range gs.Categories.All() {
+		if cat.IsUsed() {
+			dst[28+i] = 1.0
+		}
+	}
+}
+
+// RichInputSize is the number of inputs written by a RichEncoder:
+// the InputSize one-hot inputs, 5 berry counts, 9 category scores, the rounds left and the score.
+const RichInputSize = InputSize + 5 + game.NumCategories + 2
+
+// A RichEncoder writes the inputs of a OneHotEncoder, followed by:
+//   - how many jars hold each berry, divided by the number of jars, in the same order as the one-hot berry inputs;
+//   - what each open category would score with the current berries, divided by the most any category can score, or 0 once it is used;
+//   - the rounds left, divided by the number of rounds;
+//   - the score so far, divided by the highest possible score.
+//
+// It keeps a buffer of the berries, so it must not be used from more than one goroutine at a time.
+type RichEncoder struct {
+	berries []game.Berry
+}
+
+// Encoding returns EncodingRich.
+func (*RichEncoder) Encoding() Encoding {
+	return EncodingRich
+}
+
+// Size returns RichInputSize.
+func (*RichEncoder) Size() int {
+	return RichInputSize
+}
+
+// Encode writes the rich inputs for the game state into dst.
+func (r *RichEncoder) Encode(gs *game.GameState, dst []float64) {
+	OneHotEncoder{}.Encode(gs, dst[:InputSize])
+	clear(dst[InputSize:])
+
+	r.berries = r.berries[:0]
+	for _, jar := range gs.Jars {
+		r.berries = append(r.berries, jar.Berry)
+	}
+
+	// berry counts
+	counts := dst[InputSize : InputSize+5]
+	for _, berry := range r.berries {
+		counts[berryInputs[berry]] += 1.0 / float64(len(r.berries))
+	}
+
+	// what every open category would score
+	rules := gs.Rules
+	if rules == nil {
+		rules = game.DefaultRules()
+	}
+	scale := float64(max(maxCategoryScore(rules, len(r.berries)), 1))
+
+	prospects := dst[InputSize+5 : InputSize+5+game.NumCategories]
+	for i, cat := range gs.Categories.All() {
+		if !cat.IsUsed() {
+			prospects[i] = float64(cat.PreviewScore(r.berries)) / scale
+		}
+	}
+
+	dst[RichInputSize-2] = float64(game.NumCategories-gs.RoundsCompleted) / game.NumCategories
+	dst[RichInputSize-1] = float64(gs.Score) / maxScore
+}
+
+// maxCategoryScore returns the most points any category can score with the rules:
+// every jar holding the most valuable berry, or the biggest five of a kind payout.
+func maxCategoryScore(rules *game.RuleSet, jars int) int {
+	best := 0
+	for _, berry := range []game.Berry{game.Jumbleberry, game.Sugarberry, game.Pickleberry, game.Moonberry} {
+		best = max(best, jars*rules.Points.Of(berry), rules.FiveOfAKind.Of(berry))
+	}
+
+	return best
+}
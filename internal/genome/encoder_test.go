@@ -0,0 +1,144 @@
+package genome
+
+import (
+	"math"
+	"math/rand"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/iadams749/JumbleBerryFieldsBot/internal/game"
+)
+
+func TestEncoding_NewEncoder(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		encoding Encoding
+		want     Encoding
+		size     int
+	}{
+		{encoding: "", want: EncodingOneHot, size: InputSize},
+		{encoding: EncodingOneHot, want: EncodingOneHot, size: InputSize},
+		{encoding: EncodingRich, want: EncodingRich, size: RichInputSize},
+	}
+	for _, tt := range tests {
+		enc, err := tt.encoding.NewEncoder()
+		if err != nil {
+			t.Fatalf("NewEncoder(%q) error = %v", tt.encoding, err)
+		}
+
+		if enc.Encoding() != tt.want || enc.Size() != tt.size {
+			t.Errorf("NewEncoder(%q) = %s with %d inputs, want %s with %d", tt.encoding, enc.Encoding(), enc.Size(), tt.want, tt.size)
+		}
+	}
+
+	if _, err := Encoding("sparse").NewEncoder(); err == nil {
+		t.Errorf("NewEncoder() expected an error for an unknown encoding")
+	}
+}
+
+func TestRichEncoder_Encode(t *testing.T) {
+	t.Parallel()
+	berries, err := game.ParseBerries("M M J S X")
+	if err != nil {
+		t.Fatalf("ParseBerries() error = %v", err)
+	}
+
+	gs, err := game.NewGameFromPosition(nil, game.Position{
+		Berries:   berries,
+		RollsLeft: 1,
+		Used:      map[game.CategoryID]int{game.CatFree: 20, game.CatJumbleberry: 4},
+	})
+	if err != nil {
+		t.Fatalf("NewGameFromPosition() error = %v", err)
+	}
+
+	got := make([]float64, RichInputSize)
+	for i := range got {
+		got[i] = -1 // every input must be overwritten
+	}
+	(&RichEncoder{}).Encode(gs, got)
+
+	want := make([]float64, InputSize)
+	OneHotEncoder{}.Encode(gs, want)
+
+	// berry counts, in the order of the one-hot inputs: Jumbleberry, Sugarberry, Pickleberry, Moonberry, Pest
+	want = append(want, 0.2, 0.2, 0, 0.4, 0.2)
+
+	// category scores divided by 35, the most a category can score with the default rules
+	want = append(want, 0, 2.0/35, 0, 14.0/35, 0, 0, 0, 0, 0)
+
+	// 7 rounds left and 24 points
+	want = append(want, 7.0/9, 24.0/maxScore)
+
+	for i := range want {
+		if math.Abs(got[i]-want[i]) > 1e-12 {
+			t.Errorf("input %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestRichEncoding_Genome(t *testing.T) {
+	t.Parallel()
+	g := NewGenome(rand.New(rand.NewSource(4)), []int{8}, &RichEncoder{})
+	if g.Encoding != EncodingRich || len(g.Weights[0][0]) != RichInputSize {
+		t.Fatalf("NewGenome() has encoding %q and %d inputs, want %q and %d", g.Encoding, len(g.Weights[0][0]), EncodingRich, RichInputSize)
+	}
+
+	n, err := NewNetwork(g)
+	if err != nil {
+		t.Fatalf("NewNetwork() error = %v", err)
+	}
+
+	graph, input, output, err := g.BuildGraph()
+	if err != nil {
+		t.Fatalf("BuildGraph() error = %v", err)
+	}
+
+	// the graph and the network read the same rich inputs
+	player := GraphPlayer{Graph: graph, Input: input, Output: output, Encoder: &RichEncoder{}}
+	for seed := range int64(3) {
+		gs := game.NewGameWithRoller(nil, game.NewSeededRoller(seed))
+		gs.RollJars()
+
+		for !gs.IsOver() {
+			want, err := player.ChooseAction(gs)
+			if err != nil {
+				t.Fatalf("GraphPlayer.ChooseAction() error = %v", err)
+			}
+
+			got, err := n.ChooseAction(gs)
+			if err != nil {
+				t.Fatalf("Network.ChooseAction() error = %v", err)
+			}
+
+			if got != want {
+				t.Fatalf("Network.ChooseAction() = %v, want %v", got, want)
+			}
+
+			if err := gs.Apply(got); err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "rich.json")
+	if err := Save(path, g, TrainingInfo{}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	loaded, _, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(loaded, g) {
+		t.Errorf("loaded genome doesn't match the saved one")
+	}
+
+	// a genome with rich weights can't be run with one-hot inputs
+	g.Encoding = EncodingOneHot
+	if err := g.Validate(); err == nil {
+		t.Errorf("Validate() expected an error for weights that don't match the encoding")
+	}
+}
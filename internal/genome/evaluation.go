@@ -0,0 +1,214 @@
+package genome
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync/atomic"
+
+	"github.com/MaxHalford/eaopt"
+	"github.com/iadams749/JumbleBerryFieldsBot/internal/game"
+	"github.com/iadams749/JumbleBerryFieldsBot/internal/player"
+)
+
+// maxScore is the highest possible score in a game.
+// Fitness is the distance from it, so that minimizing fitness maximizes the score.
+const maxScore = 237.0
+
+// A Statistic reduces the scores of several games to a single number.
+type Statistic string
+
+const (
+	// StatMean is the average score.
+	StatMean Statistic = "mean"
+
+	// StatMedian is the middle score.
+	StatMedian Statistic = "median"
+
+	// StatMin is the lowest score, for training policies that avoid bad games.
+	StatMin Statistic = "min"
+)
+
+// Apply returns the statistic of the scores.
+func (s Statistic) Apply(scores []int) (float64, error) {
+	if len(scores) == 0 {
+		return 0, fmt.Errorf("no scores to apply %s to", s)
+	}
+
+	sorted := append([]int{}, scores...)
+	sort.Ints(sorted)
+
+	switch s {
+	case StatMean, "":
+		sum := 0
+		for _, score := range sorted {
+			sum += score
+		}
+		return float64(sum) / float64(len(sorted)), nil
+	case StatMedian:
+		mid := len(sorted) / 2
+		if len(sorted)%2 == 0 {
+			return float64(sorted[mid-1]+sorted[mid]) / 2, nil
+		}
+		return float64(sorted[mid]), nil
+	case StatMin:
+		return float64(sorted[0]), nil
+	default:
+		return 0, fmt.Errorf("unknown statistic %q", s)
+	}
+}
+
+// An Evaluation is shared by every genome in a population so that they are all scored on the same dice.
+// Each generation it draws a new set of seeds, one per game, and every genome plays one game with each seed.
+//
+// The seeds are only changed by NextGeneration, which must not run while genomes are being evaluated.
+// The eaopt callback is a safe place to call it.
+type Evaluation struct {
+	Games     int
+	Statistic Statistic
+
+	// Penalty is taken off the score for every invalid decision a genome makes per game, on average.
+	Penalty float64
+
+	rng   *rand.Rand
+	seeds []int64
+
+	// stalled counts the genomes that stalled in a game this generation. Genomes are evaluated in parallel.
+	stalled atomic.Int64
+}
+
+// NewEvaluation returns an Evaluation that plays the provided number of games per genome and scores them with stat.
+// The seeds of every generation are drawn from a generator seeded with seed.
+func NewEvaluation(games int, stat Statistic, seed int64) (*Evaluation, error) {
+	if games < 1 {
+		return nil, fmt.Errorf("games per evaluation must be at least 1, got %d", games)
+	}
+
+	if _, err := stat.Apply([]int{0}); err != nil {
+		return nil, err
+	}
+
+	e := &Evaluation{
+		Games:     games,
+		Statistic: stat,
+		rng:       rand.New(rand.NewSource(seed)),
+	}
+	e.drawSeeds()
+
+	return e, nil
+}
+
+// drawSeeds replaces the seeds with a new set.
+func (e *Evaluation) drawSeeds() {
+	e.seeds = make([]int64, e.Games)
+	for i := range e.seeds {
+		e.seeds[i] = e.rng.Int63()
+	}
+}
+
+// Seeds returns the seeds of the games in the current generation.
+func (e *Evaluation) Seeds() []int64 {
+	return append([]int64{}, e.seeds...)
+}
+
+// NextGeneration draws new seeds and marks every individual in the GA for re-evaluation,
+// so individuals that survive unchanged are compared on the same dice as their offspring.
+// The hall of fame is re-scored on the new seeds right away, for the same reason: a genome that got lucky
+// dice once must not stay in it with a fitness no later genome is measured against.
+func (e *Evaluation) NextGeneration(ga *eaopt.GA) error {
+	e.drawSeeds()
+
+	for i := range ga.HallOfFame {
+		indi := &ga.HallOfFame[i]
+		if indi.Genome == nil {
+			continue
+		}
+
+		setEvaluation(indi.Genome, e)
+		indi.Evaluated = false
+		if err := indi.Evaluate(); err != nil {
+			return err
+		}
+	}
+	sort.SliceStable(ga.HallOfFame, func(i, j int) bool {
+		return ga.HallOfFame[i].Fitness < ga.HallOfFame[j].Fitness
+	})
+
+	// the hall of fame doesn't count towards the stalled genomes of the generation
+	e.stalled.Store(0)
+
+	for _, pop := range ga.Populations {
+		for i := range pop.Individuals {
+			pop.Individuals[i].Evaluated = false
+		}
+	}
+
+	return nil
+}
+
+// Stalled returns how many genomes stalled in at least one of their games since the last NextGeneration.
+func (e *Evaluation) Stalled() int {
+	return int(e.stalled.Load())
+}
+
+// Score plays one game per seed with play and returns the statistic of the scores,
+// less the Penalty for every invalid decision per game.
+// A stalled game counts with the score it had when it stalled.
+func (e *Evaluation) Score(play func(r game.Roller) (GameResult, error)) (float64, error) {
+	scores := make([]int, len(e.seeds))
+	invalid, stalled := 0, false
+
+	for i, seed := range e.seeds {
+		result, err := play(game.NewSeededRoller(seed))
+		if errors.Is(err, player.ErrStalled) {
+			stalled = true
+		} else if err != nil {
+			return 0, err
+		}
+
+		scores[i] = result.Score
+		invalid += result.Invalid
+	}
+
+	if stalled {
+		e.stalled.Add(1)
+	}
+
+	score, err := e.Statistic.Apply(scores)
+	if err != nil {
+		return 0, err
+	}
+
+	return score - e.Penalty*float64(invalid)/float64(len(scores)), nil
+}
+
+// WithEvaluation returns a GenomeFactory whose genomes are scored with e.
+func (f GenomeFactory) WithEvaluation(e *Evaluation) GenomeFactory {
+	return func(rng *rand.Rand) eaopt.Genome {
+		g := f(rng)
+		setEvaluation(g, e)
+		return g
+	}
+}
+
+// setEvaluation makes g score itself with e.
+func setEvaluation(g eaopt.Genome, e *Evaluation) {
+	switch genome := g.(type) {
+	case *Genome:
+		genome.Eval = e
+	case *NEATGenome:
+		genome.Eval = e
+	}
+}
+
+// evaluationOf returns the evaluation g is scored with, or nil if it has none.
+func evaluationOf(g eaopt.Genome) *Evaluation {
+	switch genome := g.(type) {
+	case *Genome:
+		return genome.Eval
+	case *NEATGenome:
+		return genome.Eval
+	}
+	return nil
+}
@@ -0,0 +1,223 @@
+package genome
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/MaxHalford/eaopt"
+	"github.com/iadams749/JumbleBerryFieldsBot/internal/game"
+	"github.com/iadams749/JumbleBerryFieldsBot/internal/player"
+)
+
+func TestStatistic_Apply(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		stat    Statistic
+		scores  []int
+		want    float64
+		wantErr bool
+	}{
+		{name: "mean", stat: StatMean, scores: []int{10, 20, 60}, want: 30},
+		{name: "empty statistic is the mean", stat: "", scores: []int{1, 2}, want: 1.5},
+		{name: "median of odd count", stat: StatMedian, scores: []int{60, 10, 20}, want: 20},
+		{name: "median of even count", stat: StatMedian, scores: []int{40, 10, 20, 30}, want: 25},
+		{name: "min", stat: StatMin, scores: []int{40, 10, 20}, want: 10},
+		{name: "no scores", stat: StatMean, scores: nil, wantErr: true},
+		{name: "unknown statistic", stat: "max", scores: []int{1}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := tt.stat.Apply(tt.scores)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Apply() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Apply() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewEvaluation(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		games   int
+		stat    Statistic
+		wantErr bool
+	}{
+		{name: "valid", games: 3, stat: StatMedian},
+		{name: "no games", games: 0, stat: StatMean, wantErr: true},
+		{name: "unknown statistic", games: 3, stat: "max", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e, err := NewEvaluation(tt.games, tt.stat, 1)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewEvaluation() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && len(e.Seeds()) != tt.games {
+				t.Errorf("NewEvaluation() has %d seeds, want %d", len(e.Seeds()), tt.games)
+			}
+		})
+	}
+}
+
+func TestEvaluation_NextGeneration(t *testing.T) {
+	t.Parallel()
+	e, err := NewEvaluation(2, StatMean, 1)
+	if err != nil {
+		t.Fatalf("NewEvaluation() error = %v", err)
+	}
+
+	other, _ := NewEvaluation(2, StatMean, 1)
+	if !reflect.DeepEqual(e.Seeds(), other.Seeds()) {
+		t.Errorf("evaluations with the same seed drew %v and %v", e.Seeds(), other.Seeds())
+	}
+
+	ga := newTestGA(t, NewGenomeFactory([]int{3}, nil).WithEvaluation(e))
+	before := e.Seeds()
+	if err := e.NextGeneration(ga); err != nil {
+		t.Fatalf("NextGeneration() error = %v", err)
+	}
+
+	if reflect.DeepEqual(before, e.Seeds()) {
+		t.Errorf("NextGeneration() kept the seeds %v", before)
+	}
+
+	for _, pop := range ga.Populations {
+		for _, indi := range pop.Individuals {
+			if indi.Evaluated {
+				t.Fatalf("NextGeneration() left an individual marked as evaluated")
+			}
+			if indi.Genome.(*Genome).Eval != e {
+				t.Fatalf("individual is not scored with the shared evaluation")
+			}
+		}
+	}
+}
+
+func TestEvaluation_NextGeneration_RescoresHallOfFame(t *testing.T) {
+	t.Parallel()
+	e, err := NewEvaluation(2, StatMean, 1)
+	if err != nil {
+		t.Fatalf("NewEvaluation() error = %v", err)
+	}
+
+	ga := newTestGA(t, NewGenomeFactory([]int{3}, nil).WithEvaluation(e))
+
+	// the worst of the hall of fame got lucky dice, and the other one has no evaluation, like after a restore
+	ga.HallOfFame[1].Fitness = -1000
+	ga.HallOfFame[0].Genome.(*Genome).Eval = nil
+
+	if err := e.NextGeneration(ga); err != nil {
+		t.Fatalf("NextGeneration() error = %v", err)
+	}
+
+	for i, indi := range ga.HallOfFame {
+		want, err := indi.Genome.Clone().Evaluate()
+		if err != nil {
+			t.Fatalf("Evaluate() error = %v", err)
+		}
+		if indi.Fitness != want {
+			t.Errorf("hall of fame %d has fitness %v, want %v on the new seeds", i, indi.Fitness, want)
+		}
+		if indi.Genome.(*Genome).Eval != e {
+			t.Errorf("hall of fame %d is not scored with the shared evaluation", i)
+		}
+	}
+
+	if ga.HallOfFame[0].Fitness > ga.HallOfFame[1].Fitness {
+		t.Errorf("hall of fame is not sorted: %v, %v", ga.HallOfFame[0].Fitness, ga.HallOfFame[1].Fitness)
+	}
+}
+
+func TestGenome_Evaluate_CommonRandomNumbers(t *testing.T) {
+	t.Parallel()
+	e, err := NewEvaluation(3, StatMean, 7)
+	if err != nil {
+		t.Fatalf("NewEvaluation() error = %v", err)
+	}
+
+	g := NewGenomeFactory([]int{4}, nil).WithEvaluation(e)(rand.New(rand.NewSource(0))).(*Genome)
+
+	first, err := g.Evaluate()
+	if err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+
+	// a clone shares the evaluation, so it plays the same games
+	second, err := g.Clone().(*Genome).Evaluate()
+	if err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+
+	if first != second {
+		t.Errorf("Evaluate() = %v then %v on the same seeds", first, second)
+	}
+
+	graph, input, output, err := g.BuildGraph()
+	if err != nil {
+		t.Fatalf("BuildGraph() error = %v", err)
+	}
+
+	var scores []int
+	for _, seed := range e.Seeds() {
+		scores = append(scores, PlayGameWithRoller(graph, input, output, game.NewSeededRoller(seed)))
+	}
+
+	mean, _ := StatMean.Apply(scores)
+	if want := maxScore - mean; first != want {
+		t.Errorf("Evaluate() = %v, want %v", first, want)
+	}
+}
+
+func TestEvaluation_Score(t *testing.T) {
+	t.Parallel()
+	e, err := NewEvaluation(4, StatMean, 1)
+	if err != nil {
+		t.Fatalf("NewEvaluation() error = %v", err)
+	}
+	e.Penalty = 2
+
+	// every game scores 100 with 3 invalid decisions, and the second one stalls
+	games := 0
+	play := func(r game.Roller) (GameResult, error) {
+		games++
+		if games == 2 {
+			return GameResult{Score: 100, Invalid: 3}, fmt.Errorf("%w in round 4", player.ErrStalled)
+		}
+		return GameResult{Score: 100, Invalid: 3}, nil
+	}
+
+	got, err := e.Score(play)
+	if err != nil {
+		t.Fatalf("Score() error = %v", err)
+	}
+
+	if want := 100.0 - 2*3; got != want {
+		t.Errorf("Score() = %v, want %v", got, want)
+	}
+
+	if e.Stalled() != 1 {
+		t.Errorf("Stalled() = %d, want 1", e.Stalled())
+	}
+
+	if err := e.NextGeneration(&eaopt.GA{}); err != nil {
+		t.Fatalf("NextGeneration() error = %v", err)
+	}
+	if e.Stalled() != 0 {
+		t.Errorf("Stalled() = %d after NextGeneration(), want 0", e.Stalled())
+	}
+
+	failing := func(game.Roller) (GameResult, error) { return GameResult{}, errors.New("no legal action") }
+	if _, err := e.Score(failing); err == nil {
+		t.Errorf("Score() expected an error from a game that failed")
+	}
+}
@@ -11,31 +11,67 @@ import (
 // It is used for the Minimize() call when training using the eaopt package.
 type GenomeFactory func(rng *rand.Rand) eaopt.Genome
 
-// NewGenomeFactory creates a GenomeFactory that initalizes genomes with the provided hiddel layer size.
-func NewGenomeFactory(hiddenLayerSizes []int) GenomeFactory {
+// NewGenomeFactory creates a GenomeFactory that initalizes genomes with the provided hiddel layer size,
+// for networks that read their inputs from enc and apply acts to their layers, like NewGenome.
+func NewGenomeFactory(hiddenLayerSizes []int, enc Encoder, acts ...Activation) GenomeFactory {
 	return func(rng *rand.Rand) eaopt.Genome {
-		return NewGenome(rng, hiddenLayerSizes)
+		return NewGenome(rng, hiddenLayerSizes, enc, acts...)
 	}
 }
 
-// NewGenome creates a new genome with the provided hidden layer sizes.
-// Values are initalized usign glorot initalization.
-func NewGenome(rng *rand.Rand, hiddenLayerSizes []int) *Genome {
+// WithDecoding returns a GenomeFactory whose genomes turn their outputs into actions with d.
+func (f GenomeFactory) WithDecoding(d Decoding) GenomeFactory {
+	return func(rng *rand.Rand) eaopt.Genome {
+		g := f(rng)
+		switch genome := g.(type) {
+		case *Genome:
+			genome.Decoding = d
+		case *NEATGenome:
+			genome.Decoding = d
+		}
+		return g
+	}
+}
+
+// NewGenome creates a new genome with the provided hidden layer sizes, for a network that reads its inputs from enc.
+// The genome records the encoding, so the network is always run with the inputs it was trained on.
+// If enc is nil, the default OneHotEncoder is used.
+//
+// acts holds the activation of each hidden layer and then the output layer. If it is empty, DefaultActivations is used.
+// It panics if acts doesn't pass CheckActivations.
+// The weights and biases of each layer are initialized for its activation and its number of inputs and outputs.
+func NewGenome(rng *rand.Rand, hiddenLayerSizes []int, enc Encoder, acts ...Activation) *Genome {
+	if enc == nil {
+		enc = OneHotEncoder{}
+	}
+
+	if len(acts) == 0 {
+		acts = DefaultActivations(len(hiddenLayerSizes))
+	}
+
+	if err := CheckActivations(acts, len(hiddenLayerSizes)+1); err != nil {
+		panic(err.Error())
+	}
+
 	// initalize the genome
 	g := &Genome{}
 	g.HiddenLayerSizes = hiddenLayerSizes
+	g.Encoding = enc.Encoding()
+	g.Activations = append([]Activation{}, acts...)
 
 	// build a list of size for each layer, including input/hidden/output
-	sizes := []int{InputSize}
+	sizes := []int{enc.Size()}
 	sizes = append(sizes, hiddenLayerSizes...)
 	sizes = append(sizes, OutputSize)
 
 	// initalize the arrays according to the hidden sizes
 	for i := 1; i < len(sizes); i++ {
+		act, fanIn, fanOut := acts[i-1], sizes[i-1], sizes[i]
+
 		// initalize the biases
 		bias := make([]float64, sizes[i])
 		for idx := range sizes[i] {
-			bias[idx] = glorot(rng, InputSize, OutputSize)
+			bias[idx] = act.initWeight(rng, fanIn, fanOut)
 		}
 		g.Biases = append(g.Biases, bias)
 
@@ -44,7 +80,7 @@ func NewGenome(rng *rand.Rand, hiddenLayerSizes []int) *Genome {
 		for outerIdx := range sizes[i] {
 			weight := make([]float64, sizes[i-1])
 			for innerIdx := range sizes[i-1] {
-				weight[innerIdx] = glorot(rng, InputSize, OutputSize)
+				weight[innerIdx] = act.initWeight(rng, fanIn, fanOut)
 			}
 			weights[outerIdx] = weight
 		}
@@ -60,3 +96,9 @@ func glorot(rng *rand.Rand, fanIn, fanOut int) float64 {
 	limit := math.Sqrt(6.0 / float64(fanIn+fanOut))
 	return rng.Float64()*(2*limit) - limit // Scale to [-limit, limit]
 }
+
+// he initializes a float64 using the He uniform distribution, which suits layers with a ReLU activation.
+func he(rng *rand.Rand, fanIn int) float64 {
+	limit := math.Sqrt(6.0 / float64(fanIn))
+	return rng.Float64()*(2*limit) - limit // Scale to [-limit, limit]
+}
@@ -65,7 +65,7 @@ func TestNewGenome(t *testing.T) {
 				return
 			}
 
-			if got := NewGenome(tt.args.rng, tt.args.hiddenLayerSizes); !reflect.DeepEqual(got, &want) {
+			if got := NewGenome(tt.args.rng, tt.args.hiddenLayerSizes, nil); !reflect.DeepEqual(got, &want) {
 				t.Errorf("NewGenome() = %v, want %v", got, want)
 			}
 		})
@@ -127,7 +127,7 @@ func TestNewGenomeFactory(t *testing.T) {
 				return
 			}
 
-			factory := NewGenomeFactory(tt.args.hiddenLayerSizes)
+			factory := NewGenomeFactory(tt.args.hiddenLayerSizes, nil)
 
 			if got := factory(tt.args.rng); !reflect.DeepEqual(got, &want) {
 				t.Errorf("NewGenomeFactory() = %v, want %v", got, want)
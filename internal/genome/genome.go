@@ -11,7 +11,7 @@ import (
 
 const (
 
-	// InputSize represents the number of inputs into the neural network.
+	// InputSize represents the number of inputs into the neural network with the default one-hot encoding.
 	// Each berry/pest takes 5 inputs so that they can be one-hot encoded. This results in 25 inputs for the jars
 	// Each category takes one input to represent whether or not it has been used.
 	// Three inputs are necessary to one-hot encode how many rolls are left.
@@ -38,18 +38,46 @@ type Genome struct {
 
 	// Weights is a three-dimensional array of float64 representing the weights of the neural network.
 	Weights [][][]float64 `json:"weights"`
+
+	// Encoding is how game states are turned into the inputs of the network. It is EncodingOneHot if empty.
+	Encoding Encoding `json:"encoding,omitempty"`
+
+	// Decoding is how the outputs of the network are turned into actions. It is DecodingArgmax if empty.
+	Decoding Decoding `json:"decoding,omitempty"`
+
+	// Activations holds the activation of each hidden layer and then the output layer.
+	// It is DefaultActivations if empty, as in genomes saved before activations were configurable.
+	Activations []Activation `json:"activations,omitempty"`
+
+	// Eval decides which games the genome plays when it is evaluated.
+	// It is shared between every genome in a population. If it is nil, a single random game is played.
+	Eval *Evaluation `json:"-"`
 }
 
+// Evaluate plays games with the genome and returns how far its score is from the maximum score.
 func (g *Genome) Evaluate() (float64, error) {
-	graph, input, output, err := g.BuildGraph()
+	network, err := NewNetwork(g)
+	if err != nil {
+		return 0.0, err
+	}
+
+	return evaluate(network, g.Eval)
+}
+
+// evaluate plays the games of eval with the network and returns how far its score is from the maximum score.
+// If eval is nil, a single random game is played.
+func evaluate(network *Network, eval *Evaluation) (float64, error) {
+	if eval == nil {
+		score := network.PlayGame(nil)
+		return maxScore - float64(score), nil
+	}
+
+	score, err := eval.Score(network.Play)
 	if err != nil {
 		return 0.0, err
 	}
 
-	score := PlayGameFromGraph(graph, input, output)
-	fitness := 237.0 - float64(score)
-	
-	return fitness, nil
+	return maxScore - score, nil
 }
 
 // Mutate applies random Gaussian noise to weights and biases to simulate mutation.
@@ -109,6 +137,10 @@ func (g *Genome) Crossover(other eaopt.Genome, rng *rand.Rand) {
 func (g *Genome) Clone() eaopt.Genome {
 	copyG := &Genome{
 		HiddenLayerSizes: append([]int{}, g.HiddenLayerSizes...), // Copy HiddenLayerSizes
+		Encoding:         g.Encoding,
+		Decoding:         g.Decoding,
+		Activations:      append([]Activation(nil), g.Activations...),
+		Eval:             g.Eval, // The evaluation is shared, not copied
 	}
 
 	// Deep copy Biases
@@ -140,19 +172,25 @@ func (g *Genome) BuildGraph() (graph *gorgonia.ExprGraph, input *gorgonia.Node,
 		return
 	}
 
+	enc, err := g.Encoding.NewEncoder()
+	if err != nil {
+		return
+	}
+
 	// Create input node
 	input = gorgonia.NewMatrix(graph,
 		tensor.Float64,
-		gorgonia.WithShape(1, InputSize), // batch size 1
+		gorgonia.WithShape(1, enc.Size()), // batch size 1
 		gorgonia.WithName("input"),
 		gorgonia.WithInit(gorgonia.Zeroes()),
 	)
 
 	x := input
+	acts := g.layerActivations()
 
 	// Build each layer
 	for i := range g.Weights {
-		wShape := tensor.Shape{len(g.Weights[i][0]), len(g.Weights[i]), }
+		wShape := tensor.Shape{len(g.Weights[i][0]), len(g.Weights[i])}
 		bShape := tensor.Shape{1, len(g.Biases[i])}
 
 		// Create weight node
@@ -184,13 +222,9 @@ func (g *Genome) BuildGraph() (graph *gorgonia.ExprGraph, input *gorgonia.Node,
 			panic(err.Error())
 		}
 
-		// Apply activation (ReLU for all hidden layers, no activation for last layer)
-		if i < len(g.Weights)-1 {
-			if x, err = gorgonia.Rectify(z); err != nil {
-				panic(err.Error())
-			}
-		} else {
-			x = z // Final layer, no activation (or add softmax here if needed)
+		// Apply the activation of the layer
+		if x, err = acts[i].node(z); err != nil {
+			panic(err.Error())
 		}
 	}
 
@@ -198,10 +232,19 @@ func (g *Genome) BuildGraph() (graph *gorgonia.ExprGraph, input *gorgonia.Node,
 	return
 }
 
+// layerActivations returns the activation of each layer, filling in the defaults for genomes that don't record them.
+func (g *Genome) layerActivations() []Activation {
+	if len(g.Activations) == 0 {
+		return DefaultActivations(len(g.HiddenLayerSizes))
+	}
+
+	return g.Activations
+}
+
 func flatten2D(matrix [][]float64) []float64 {
 	flat := make([]float64, 0, len(matrix)*len(matrix[0]))
 	for _, row := range matrix {
 		flat = append(flat, row...)
 	}
 	return flat
-}
\ No newline at end of file
+}
@@ -0,0 +1,594 @@
+package genome
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+
+	"github.com/MaxHalford/eaopt"
+)
+
+const (
+	// neatAddConnRate is the probability that a mutation adds a connection between two unconnected nodes.
+	neatAddConnRate = 0.05
+
+	// neatAddNodeRate is the probability that a mutation splits a connection with a new hidden node.
+	neatAddNodeRate = 0.03
+
+	// neatReplaceRate is the probability that a mutated weight or bias is drawn again instead of nudged.
+	neatReplaceRate = 0.1
+)
+
+// A NodeGene is a hidden or output node of a NEATGenome.
+// The inputs of the network are the nodes 0 to the input size of the encoding, and the outputs follow them,
+// so the output nodes are always present and hidden nodes have the highest IDs.
+type NodeGene struct {
+	ID   int     `json:"id"`
+	Bias float64 `json:"bias"`
+}
+
+// A ConnGene is a weighted connection from one node of a NEATGenome to another.
+// Connections carrying the same innovation number came from the same structural mutation,
+// which is how crossover lines up the genes of two genomes.
+type ConnGene struct {
+	Innovation int     `json:"innovation"`
+	In         int     `json:"in"`
+	Out        int     `json:"out"`
+	Weight     float64 `json:"weight"`
+	Enabled    bool    `json:"enabled"`
+}
+
+// A NEATGenome is an eaopt.Genome that evolves the structure of its network as well as its weights,
+// following NEAT: mutations add connections and split them with new nodes, and every structural change gets
+// an innovation number so that genomes with different structures can still be crossed over.
+//
+// It starts with every input connected to every output. Hidden nodes use tanh, and the outputs are left as they
+// are, so a NEATGenome is read by the same Encoder and Decoder as a Genome. The network is always feed-forward.
+type NEATGenome struct {
+	// Nodes holds the output and hidden nodes, sorted by ID.
+	Nodes []NodeGene `json:"nodes"`
+
+	// Conns holds the connections, sorted by innovation number.
+	Conns []ConnGene `json:"connections"`
+
+	// Encoding is how game states are turned into the inputs of the network. It is EncodingOneHot if empty.
+	Encoding Encoding `json:"encoding,omitempty"`
+
+	// Decoding is how the outputs of the network are turned into actions. It is DecodingArgmax if empty.
+	Decoding Decoding `json:"decoding,omitempty"`
+
+	// Eval decides which games the genome plays when it is evaluated, like Genome.Eval.
+	Eval *Evaluation `json:"-"`
+
+	// Innovations numbers the structural mutations of the population. It is shared between every genome in
+	// a population, and structural mutations are skipped if it is nil.
+	Innovations *Innovations `json:"-"`
+
+	// fitness is the fitness of the last evaluation of the genome or, for offspring, of the parent it was
+	// cloned from, if evaluated is true. Crossover takes the structure of the fitter parent from it.
+	fitness   float64
+	evaluated bool
+}
+
+// Innovations hands out innovation numbers and node IDs, so the same structural mutation gets the same numbers
+// in every genome of a population. It is safe to use from more than one goroutine.
+type Innovations struct {
+	mu sync.Mutex
+
+	nextConn int
+	nextNode int
+
+	// conns maps the nodes a connection joins to its innovation number,
+	// and splits maps the innovation number of a split connection to the node that split it.
+	conns  map[[2]int]int
+	splits map[int]int
+}
+
+// NewInnovations returns the Innovations of a population of networks with the provided number of inputs.
+// The connections from every input to every output, that every genome starts with, are numbered first.
+func NewInnovations(inputs int) *Innovations {
+	in := &Innovations{
+		nextNode: inputs + OutputSize,
+		conns:    make(map[[2]int]int),
+		splits:   make(map[int]int),
+	}
+
+	for i := range inputs {
+		for o := range OutputSize {
+			in.conn(i, inputs+o)
+		}
+	}
+
+	return in
+}
+
+// Conn returns the innovation number of the connection between the nodes, numbering it if it's new.
+func (in *Innovations) Conn(from, to int) int {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+
+	return in.conn(from, to)
+}
+
+// conn is Conn without the lock.
+func (in *Innovations) conn(from, to int) int {
+	key := [2]int{from, to}
+	if innovation, ok := in.conns[key]; ok {
+		return innovation
+	}
+
+	in.conns[key] = in.nextConn
+	in.nextConn++
+
+	return in.conns[key]
+}
+
+// Split returns the ID of the node that splits the connection with the innovation number,
+// or a new node if g already has that node from splitting the connection before.
+func (in *Innovations) Split(g *NEATGenome, innovation int) int {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+
+	if id, ok := in.splits[innovation]; ok && g.node(id) < 0 {
+		return id
+	}
+
+	id := in.nextNode
+	in.nextNode++
+	if _, ok := in.splits[innovation]; !ok {
+		in.splits[innovation] = id
+	}
+
+	return id
+}
+
+// innovationsJSON is the form Innovations are saved in. The connections are listed, since JSON objects can't have
+// pairs of nodes as keys, and both lists are sorted by innovation number so the same state always saves the same.
+type innovationsJSON struct {
+	NextConn int               `json:"nextConn"`
+	NextNode int               `json:"nextNode"`
+	Conns    []innovationConn  `json:"connections"`
+	Splits   []innovationSplit `json:"splits"`
+}
+
+type innovationConn struct {
+	Innovation int `json:"innovation"`
+	In         int `json:"in"`
+	Out        int `json:"out"`
+}
+
+type innovationSplit struct {
+	Innovation int `json:"innovation"`
+	Node       int `json:"node"`
+}
+
+// MarshalJSON encodes every connection and split numbered so far, and the next numbers to hand out.
+func (in *Innovations) MarshalJSON() ([]byte, error) {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+
+	out := innovationsJSON{NextConn: in.nextConn, NextNode: in.nextNode}
+	for nodes, innovation := range in.conns {
+		out.Conns = append(out.Conns, innovationConn{Innovation: innovation, In: nodes[0], Out: nodes[1]})
+	}
+	for innovation, node := range in.splits {
+		out.Splits = append(out.Splits, innovationSplit{Innovation: innovation, Node: node})
+	}
+
+	sort.Slice(out.Conns, func(i, j int) bool { return out.Conns[i].Innovation < out.Conns[j].Innovation })
+	sort.Slice(out.Splits, func(i, j int) bool { return out.Splits[i].Innovation < out.Splits[j].Innovation })
+
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON decodes Innovations encoded with MarshalJSON. It returns an error if a number that was handed out
+// isn't below the next one, which would hand it out again.
+func (in *Innovations) UnmarshalJSON(data []byte) error {
+	var saved innovationsJSON
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return err
+	}
+
+	conns := make(map[[2]int]int, len(saved.Conns))
+	for _, c := range saved.Conns {
+		if c.Innovation < 0 || c.Innovation >= saved.NextConn {
+			return fmt.Errorf("invalid innovations: connection %d is not below the next innovation %d", c.Innovation, saved.NextConn)
+		}
+		conns[[2]int{c.In, c.Out}] = c.Innovation
+	}
+
+	splits := make(map[int]int, len(saved.Splits))
+	for _, s := range saved.Splits {
+		if s.Node < 0 || s.Node >= saved.NextNode {
+			return fmt.Errorf("invalid innovations: node %d is not below the next node %d", s.Node, saved.NextNode)
+		}
+		splits[s.Innovation] = s.Node
+	}
+
+	in.mu.Lock()
+	defer in.mu.Unlock()
+
+	in.nextConn, in.nextNode, in.conns, in.splits = saved.NextConn, saved.NextNode, conns, splits
+	return nil
+}
+
+// NewNEATFactory creates a GenomeFactory that initializes NEAT genomes for networks that read their inputs from enc.
+// If enc is nil, the default OneHotEncoder is used. Every genome from the factory shares the Innovations.
+func NewNEATFactory(enc Encoder, innovations *Innovations) GenomeFactory {
+	return func(rng *rand.Rand) eaopt.Genome {
+		return NewNEATGenome(rng, enc, innovations)
+	}
+}
+
+// NewNEATGenome creates a NEAT genome that connects every input of enc to every output, with random weights.
+// If enc is nil, the default OneHotEncoder is used.
+func NewNEATGenome(rng *rand.Rand, enc Encoder, innovations *Innovations) *NEATGenome {
+	if enc == nil {
+		enc = OneHotEncoder{}
+	}
+
+	inputs := enc.Size()
+	g := &NEATGenome{Encoding: enc.Encoding(), Innovations: innovations}
+
+	for o := range OutputSize {
+		g.Nodes = append(g.Nodes, NodeGene{ID: inputs + o, Bias: glorot(rng, inputs, OutputSize)})
+	}
+
+	// the numbering matches NewInnovations
+	for i := range inputs {
+		for o := range OutputSize {
+			g.Conns = append(g.Conns, ConnGene{
+				Innovation: i*OutputSize + o,
+				In:         i,
+				Out:        inputs + o,
+				Weight:     glorot(rng, inputs, OutputSize),
+				Enabled:    true,
+			})
+		}
+	}
+
+	return g
+}
+
+// inputSize returns the number of inputs of the network, which is the number of inputs of its encoding.
+func (g *NEATGenome) inputSize() int {
+	enc, err := g.Encoding.NewEncoder()
+	if err != nil {
+		return 0
+	}
+
+	return enc.Size()
+}
+
+// node returns the index of the node with the ID in Nodes, or -1 if there isn't one.
+func (g *NEATGenome) node(id int) int {
+	i := sort.Search(len(g.Nodes), func(i int) bool { return g.Nodes[i].ID >= id })
+	if i < len(g.Nodes) && g.Nodes[i].ID == id {
+		return i
+	}
+
+	return -1
+}
+
+// HiddenNodes returns the number of hidden nodes in the network.
+func (g *NEATGenome) HiddenNodes() int {
+	return len(g.Nodes) - OutputSize
+}
+
+// EnabledConns returns the number of connections the network uses.
+func (g *NEATGenome) EnabledConns() int {
+	enabled := 0
+	for _, c := range g.Conns {
+		if c.Enabled {
+			enabled++
+		}
+	}
+
+	return enabled
+}
+
+// Evaluate plays games with the genome and returns how far its score is from the maximum score.
+func (g *NEATGenome) Evaluate() (float64, error) {
+	network, err := NewNEATNetwork(g)
+	if err != nil {
+		return 0.0, err
+	}
+
+	fitness, err := evaluate(network, g.Eval)
+	if err != nil {
+		return 0.0, err
+	}
+
+	g.fitness, g.evaluated = fitness, true
+	return fitness, nil
+}
+
+// Mutate nudges the weights and biases like Genome.Mutate, and sometimes adds a connection or a node.
+func (g *NEATGenome) Mutate(rng *rand.Rand) {
+	const mutationRate = 0.1     // Probability of each weight/bias being mutated
+	const mutationStrength = 0.5 // Standard deviation of Gaussian noise
+
+	mutate := func(v float64) float64 {
+		if rng.Float64() < neatReplaceRate {
+			return rng.NormFloat64()
+		}
+		return v + rng.NormFloat64()*mutationStrength
+	}
+
+	for i := range g.Nodes {
+		if rng.Float64() < mutationRate {
+			g.Nodes[i].Bias = mutate(g.Nodes[i].Bias)
+		}
+	}
+
+	for i := range g.Conns {
+		if rng.Float64() < mutationRate {
+			g.Conns[i].Weight = mutate(g.Conns[i].Weight)
+		}
+	}
+
+	if g.Innovations == nil {
+		return
+	}
+
+	if rng.Float64() < neatAddConnRate {
+		g.addConn(rng)
+	}
+
+	if rng.Float64() < neatAddNodeRate {
+		g.addNode(rng)
+	}
+}
+
+// addConn connects two nodes that aren't connected yet, as long as it doesn't make a cycle.
+// It gives up after a few tries, since most pairs are connected in a small network.
+func (g *NEATGenome) addConn(rng *rand.Rand) {
+	inputs := g.inputSize()
+
+	for range 20 {
+		// the source is an input or a hidden node, the target an output or a hidden node
+		from := rng.Intn(inputs + g.HiddenNodes())
+		if from >= inputs {
+			from = g.Nodes[OutputSize+from-inputs].ID
+		}
+		to := g.Nodes[rng.Intn(len(g.Nodes))].ID
+
+		if from == to || g.hasConn(from, to) || g.reaches(to, from) {
+			continue
+		}
+
+		g.insertConn(ConnGene{
+			Innovation: g.Innovations.Conn(from, to),
+			In:         from,
+			Out:        to,
+			Weight:     rng.NormFloat64(),
+			Enabled:    true,
+		})
+		return
+	}
+}
+
+// addNode disables a random enabled connection and replaces it with a new node and two connections.
+// The connection into the node has a weight of 1 and the one out of it keeps the old weight,
+// so the network starts out behaving almost like it did before.
+func (g *NEATGenome) addNode(rng *rand.Rand) {
+	var enabled []int
+	for i, c := range g.Conns {
+		if c.Enabled {
+			enabled = append(enabled, i)
+		}
+	}
+	if len(enabled) == 0 {
+		return
+	}
+
+	split := &g.Conns[enabled[rng.Intn(len(enabled))]]
+	split.Enabled = false
+	old := *split
+
+	id := g.Innovations.Split(g, old.Innovation)
+	g.Nodes = append(g.Nodes, NodeGene{ID: id})
+	sort.Slice(g.Nodes, func(i, j int) bool { return g.Nodes[i].ID < g.Nodes[j].ID })
+
+	g.insertConn(ConnGene{Innovation: g.Innovations.Conn(old.In, id), In: old.In, Out: id, Weight: 1, Enabled: true})
+	g.insertConn(ConnGene{Innovation: g.Innovations.Conn(id, old.Out), In: id, Out: old.Out, Weight: old.Weight, Enabled: true})
+}
+
+// hasConn returns true if the genome has a connection between the nodes, enabled or not.
+func (g *NEATGenome) hasConn(from, to int) bool {
+	for _, c := range g.Conns {
+		if c.In == from && c.Out == to {
+			return true
+		}
+	}
+
+	return false
+}
+
+// reaches returns true if there is a path of enabled connections from one node to the other.
+func (g *NEATGenome) reaches(from, to int) bool {
+	seen := map[int]bool{from: true}
+	stack := []int{from}
+
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if node == to {
+			return true
+		}
+
+		for _, c := range g.Conns {
+			if c.Enabled && c.In == node && !seen[c.Out] {
+				seen[c.Out] = true
+				stack = append(stack, c.Out)
+			}
+		}
+	}
+
+	return false
+}
+
+// insertConn adds the connection, keeping the connections sorted by innovation number.
+func (g *NEATGenome) insertConn(c ConnGene) {
+	i := sort.Search(len(g.Conns), func(i int) bool { return g.Conns[i].Innovation >= c.Innovation })
+	g.Conns = append(g.Conns, ConnGene{})
+	copy(g.Conns[i+1:], g.Conns[i:])
+	g.Conns[i] = c
+}
+
+// Crossover replaces both genomes with children of the two, which have the structure of the fitter parent and
+// take the weights of the genes both parents have from either at random. eaopt doesn't order the parents, so
+// the fitter one is the one with the lower fitness in its last evaluation. If either hasn't been evaluated,
+// or they are as fit, the receiver's structure is kept.
+func (g *NEATGenome) Crossover(other eaopt.Genome, rng *rand.Rand) {
+	otherGenome, ok := other.(*NEATGenome)
+	if !ok {
+		panic("Cannot cast eaopt.Genome as *NEATGenome")
+	}
+
+	fitter, weaker := g, otherGenome
+	if g.evaluated && otherGenome.evaluated && otherGenome.fitness < g.fitness {
+		fitter, weaker = otherGenome, g
+	}
+
+	first := crossNEAT(fitter, weaker, rng)
+	second := crossNEAT(fitter, weaker, rng)
+	*g, *otherGenome = *first, *second
+}
+
+// crossNEAT returns a child with the structure of the fitter parent. Genes both parents have are taken from
+// either at random, and a gene disabled in either parent is usually disabled in the child.
+func crossNEAT(fitter, other *NEATGenome, rng *rand.Rand) *NEATGenome {
+	child := fitter.Clone().(*NEATGenome)
+
+	j := 0
+	for i := range child.Conns {
+		for j < len(other.Conns) && other.Conns[j].Innovation < child.Conns[i].Innovation {
+			j++
+		}
+		if j == len(other.Conns) || other.Conns[j].Innovation != child.Conns[i].Innovation {
+			continue
+		}
+
+		if rng.Float64() < 0.5 {
+			child.Conns[i].Weight = other.Conns[j].Weight
+		}
+
+		// re-enabling a connection could close a cycle, so a child can't enable what the fitter parent disabled
+		if child.Conns[i].Enabled && !other.Conns[j].Enabled && rng.Float64() < 0.75 {
+			child.Conns[i].Enabled = false
+		}
+	}
+
+	for i := range child.Nodes {
+		if k := other.node(child.Nodes[i].ID); k >= 0 && rng.Float64() < 0.5 {
+			child.Nodes[i].Bias = other.Nodes[k].Bias
+		}
+	}
+
+	return child
+}
+
+// Clone returns a deep copy of the genome, which shares the evaluation and innovations.
+func (g *NEATGenome) Clone() eaopt.Genome {
+	return &NEATGenome{
+		Nodes:       append([]NodeGene{}, g.Nodes...),
+		Conns:       append([]ConnGene{}, g.Conns...),
+		Encoding:    g.Encoding,
+		Decoding:    g.Decoding,
+		Eval:        g.Eval,
+		Innovations: g.Innovations,
+		fitness:     g.fitness,
+		evaluated:   g.evaluated,
+	}
+}
+
+// Distance returns the compatibility distance between two genomes: the number of genes only one of them has,
+// plus the mean weight difference of the genes they share, scaled down. Genomes closer than a NEATModel's
+// threshold are in the same species.
+//
+// The genes aren't divided by the size of the genomes like in the NEAT paper, because every genome starts with
+// a connection from each input to each output, and a few new genes would be lost among them.
+func (g *NEATGenome) Distance(other *NEATGenome) float64 {
+	const weightCoeff = 0.4
+
+	matching, disjoint := 0, 0
+	weightDiff := 0.0
+
+	i, j := 0, 0
+	for i < len(g.Conns) && j < len(other.Conns) {
+		a, b := g.Conns[i], other.Conns[j]
+		switch {
+		case a.Innovation == b.Innovation:
+			matching++
+			weightDiff += abs(a.Weight - b.Weight)
+			i++
+			j++
+		case a.Innovation < b.Innovation:
+			disjoint++
+			i++
+		default:
+			disjoint++
+			j++
+		}
+	}
+	disjoint += len(g.Conns) - i + len(other.Conns) - j
+
+	distance := float64(disjoint)
+	if matching > 0 {
+		distance += weightCoeff * weightDiff / float64(matching)
+	}
+
+	return distance
+}
+
+func abs(x float64) float64 {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// Validate returns an error if the encoding or decoding is unknown, an output node is missing,
+// a connection joins nodes that don't exist, or the enabled connections make a cycle.
+func (g *NEATGenome) Validate() error {
+	enc, err := g.Encoding.NewEncoder()
+	if err != nil {
+		return fmt.Errorf("invalid NEAT genome: %w", err)
+	}
+
+	if _, err := g.Decoding.NewDecoder(); err != nil {
+		return fmt.Errorf("invalid NEAT genome: %w", err)
+	}
+
+	inputs := enc.Size()
+	if len(g.Nodes) < OutputSize {
+		return fmt.Errorf("invalid NEAT genome: expected at least %d nodes, got %d", OutputSize, len(g.Nodes))
+	}
+
+	for i, n := range g.Nodes {
+		if i < OutputSize && n.ID != inputs+i {
+			return fmt.Errorf("invalid NEAT genome: node %d has ID %d, expected output node %d", i, n.ID, inputs+i)
+		}
+		if i > 0 && n.ID <= g.Nodes[i-1].ID {
+			return fmt.Errorf("invalid NEAT genome: nodes aren't sorted by ID")
+		}
+	}
+
+	for i, c := range g.Conns {
+		if i > 0 && c.Innovation <= g.Conns[i-1].Innovation {
+			return fmt.Errorf("invalid NEAT genome: connections aren't sorted by innovation")
+		}
+		if (c.In >= inputs && g.node(c.In) < 0) || c.In < 0 || g.node(c.Out) < 0 {
+			return fmt.Errorf("invalid NEAT genome: connection %d joins nodes %d and %d, which don't all exist", c.Innovation, c.In, c.Out)
+		}
+	}
+
+	if _, err := topoOrder(g); err != nil {
+		return fmt.Errorf("invalid NEAT genome: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,144 @@
+package genome
+
+import (
+	"fmt"
+	"math"
+)
+
+// neatNet runs the network of a NEATGenome, one node at a time in topological order.
+type neatNet struct {
+	// values holds the value of every input and node from the last forward pass, inputs first.
+	values []float64
+	inputs int
+
+	nodes   []neatNode
+	outputs []int
+	out     []float64
+}
+
+// neatNode is a hidden or output node of a neatNet, with the connections into it.
+type neatNode struct {
+	index  int
+	bias   float64
+	hidden bool
+	links  []neatLink
+}
+
+// neatLink is an enabled connection into a neatNode from the value at index.
+type neatLink struct {
+	from   int
+	weight float64
+}
+
+// NewNEATNetwork builds a Network from the NEAT genome.
+// Like NewNetwork, the network copies the weights and biases, so later changes to the genome don't affect it.
+func NewNEATNetwork(g *NEATGenome) (*Network, error) {
+	if err := g.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Validate has already checked the encoding, decoding and the order of the nodes
+	enc, _ := g.Encoding.NewEncoder()
+	dec, _ := g.Decoding.NewDecoder()
+	order, _ := topoOrder(g)
+
+	inputs := enc.Size()
+	net := &neatNet{
+		values: make([]float64, inputs+len(g.Nodes)),
+		inputs: inputs,
+		out:    make([]float64, OutputSize),
+	}
+
+	// inputs keep their IDs as indices, and the nodes follow them in the order of Nodes
+	index := func(id int) int {
+		if id < inputs {
+			return id
+		}
+		return inputs + g.node(id)
+	}
+
+	for _, i := range order {
+		n := g.Nodes[i]
+		node := neatNode{index: inputs + i, bias: n.Bias, hidden: i >= OutputSize}
+		for _, c := range g.Conns {
+			if c.Enabled && c.Out == n.ID {
+				node.links = append(node.links, neatLink{from: index(c.In), weight: c.Weight})
+			}
+		}
+		net.nodes = append(net.nodes, node)
+	}
+
+	for o := range OutputSize {
+		net.outputs = append(net.outputs, inputs+o)
+	}
+
+	return &Network{net: net, encoder: enc, decoder: dec, input: make([]float64, inputs)}, nil
+}
+
+// forward computes every node in turn, applying tanh to the hidden nodes.
+func (n *neatNet) forward(inputs []float64) []float64 {
+	copy(n.values, inputs[:n.inputs])
+
+	for _, node := range n.nodes {
+		z := node.bias
+		for _, l := range node.links {
+			z += n.values[l.from] * l.weight
+		}
+
+		if node.hidden {
+			z = math.Tanh(z)
+		}
+		n.values[node.index] = z
+	}
+
+	for o, i := range n.outputs {
+		n.out[o] = n.values[i]
+	}
+
+	return n.out
+}
+
+// topoOrder returns the indices of the nodes of the genome in Nodes, ordered so that every node comes after the
+// nodes with enabled connections into it. It returns an error if the enabled connections make a cycle.
+func topoOrder(g *NEATGenome) ([]int, error) {
+	incoming := make([]int, len(g.Nodes))
+	next := make([][]int, len(g.Nodes))
+
+	for _, c := range g.Conns {
+		to, from := g.node(c.Out), g.node(c.In)
+		if !c.Enabled || to < 0 {
+			continue
+		}
+
+		// connections from the inputs don't hold back any node
+		if from >= 0 {
+			incoming[to]++
+			next[from] = append(next[from], to)
+		}
+	}
+
+	var order, ready []int
+	for i, count := range incoming {
+		if count == 0 {
+			ready = append(ready, i)
+		}
+	}
+
+	for len(ready) > 0 {
+		i := ready[0]
+		ready = ready[1:]
+		order = append(order, i)
+
+		for _, j := range next[i] {
+			if incoming[j]--; incoming[j] == 0 {
+				ready = append(ready, j)
+			}
+		}
+	}
+
+	if len(order) != len(g.Nodes) {
+		return nil, fmt.Errorf("the connections make a cycle")
+	}
+
+	return order, nil
+}
@@ -0,0 +1,215 @@
+package genome
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/MaxHalford/eaopt"
+)
+
+// A NEATModel is the eaopt.Model that breeds NEAT genomes. Each generation it splits the population into species
+// of genomes within Threshold of each other, and shares the fitness of every genome with the rest of its species.
+// Each species then gets offspring in proportion to its shared fitness, so a new structure has a few generations
+// to tune its weights in a small species before it has to compete with the whole population.
+//
+// A NEATModel remembers the species between generations, so it must be used as a pointer,
+// and with a GA that has a single population.
+type NEATModel struct {
+	// Threshold is the compatibility distance, see NEATGenome.Distance, under which a genome joins a species.
+	Threshold float64
+
+	// CrossRate is the probability that an offspring has two parents instead of one.
+	CrossRate float64
+
+	// NContestants is the size of the tournaments that pick parents within a species.
+	NContestants uint
+
+	// representatives holds a member of every species of the last generation,
+	// which the genomes of the next generation are compared with.
+	representatives []*NEATGenome
+}
+
+// championSize is the smallest species whose best genome is carried over to the next generation unchanged.
+const championSize = 5
+
+// Validate returns an error if the threshold, cross rate or tournament size are out of range.
+func (m *NEATModel) Validate() error {
+	if m.Threshold <= 0 {
+		return fmt.Errorf("species threshold must be positive, got %v", m.Threshold)
+	}
+
+	if m.CrossRate < 0 || m.CrossRate > 1 {
+		return fmt.Errorf("cross rate must be between 0 and 1, got %v", m.CrossRate)
+	}
+
+	if m.NContestants < 1 {
+		return errors.New("tournaments need at least 1 contestant")
+	}
+
+	return nil
+}
+
+// Species returns the number of species the individuals fall into, compared with the representatives of the
+// last generation that was bred, like the next call to Apply will. It doesn't change the representatives,
+// so it can report on the population the GA has just evaluated.
+func (m *NEATModel) Species(indis eaopt.Individuals) (int, error) {
+	species, err := group(indis, m.representatives, m.Threshold)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(species), nil
+}
+
+// Apply replaces the population with the offspring of its species.
+func (m *NEATModel) Apply(pop *eaopt.Population) error {
+	species, err := m.speciate(pop.Individuals, pop.RNG)
+	if err != nil {
+		return err
+	}
+
+	counts := shareOffspring(species, len(pop.Individuals))
+
+	next := make(eaopt.Individuals, 0, len(pop.Individuals))
+	for s, members := range species {
+		n := counts[s]
+		if n == 0 {
+			continue
+		}
+
+		sort.Slice(members, func(i, j int) bool { return members[i].Fitness < members[j].Fitness })
+		if len(members) >= championSize {
+			next = append(next, eaopt.NewIndividual(members[0].Genome.Clone(), pop.RNG))
+			n--
+		}
+
+		for range n {
+			first := m.tournament(members, pop.RNG)
+
+			var child *NEATGenome
+			if len(members) > 1 && pop.RNG.Float64() < m.CrossRate {
+				second := m.tournament(members, pop.RNG)
+				if second.Fitness < first.Fitness {
+					first, second = second, first
+				}
+				child = crossNEAT(first.Genome.(*NEATGenome), second.Genome.(*NEATGenome), pop.RNG)
+			} else {
+				child = first.Genome.Clone().(*NEATGenome)
+			}
+
+			child.Mutate(pop.RNG)
+			next = append(next, eaopt.NewIndividual(child, pop.RNG))
+		}
+	}
+
+	copy(pop.Individuals, next)
+	return nil
+}
+
+// speciate splits the individuals into species with group,
+// and picks a random representative for each species for the next generation.
+func (m *NEATModel) speciate(indis eaopt.Individuals, rng *rand.Rand) ([]eaopt.Individuals, error) {
+	alive, err := group(indis, m.representatives, m.Threshold)
+	if err != nil {
+		return nil, err
+	}
+
+	m.representatives = nil
+	for _, members := range alive {
+		m.representatives = append(m.representatives, members[rng.Intn(len(members))].Genome.(*NEATGenome))
+	}
+
+	return alive, nil
+}
+
+// group splits the individuals into species, comparing each with the representatives and then with the first
+// member of every new species. It returns the species that have members, and doesn't change reps.
+func group(indis eaopt.Individuals, reps []*NEATGenome, threshold float64) ([]eaopt.Individuals, error) {
+	reps = append([]*NEATGenome{}, reps...)
+	species := make([]eaopt.Individuals, len(reps))
+
+	for _, indi := range indis {
+		g, ok := indi.Genome.(*NEATGenome)
+		if !ok {
+			return nil, fmt.Errorf("NEATModel can't breed genome of type %T", indi.Genome)
+		}
+
+		placed := false
+		for s, rep := range reps {
+			if g.Distance(rep) < threshold {
+				species[s] = append(species[s], indi)
+				placed = true
+				break
+			}
+		}
+
+		if !placed {
+			reps = append(reps, g)
+			species = append(species, eaopt.Individuals{indi})
+		}
+	}
+
+	// species without members this generation die out
+	var alive []eaopt.Individuals
+	for _, members := range species {
+		if len(members) > 0 {
+			alive = append(alive, members)
+		}
+	}
+
+	return alive, nil
+}
+
+// shareOffspring returns how many offspring each species gets out of total. A species' share is its mean
+// adjusted fitness: how much better than the worst genome of the population its members are, plus one point
+// so the worst still has a chance. Remainders go to the species closest to earning another offspring.
+func shareOffspring(species []eaopt.Individuals, total int) []int {
+	worst := math.Inf(-1)
+	for _, members := range species {
+		for _, indi := range members {
+			worst = max(worst, indi.Fitness)
+		}
+	}
+
+	shares := make([]float64, len(species))
+	sum := 0.0
+	for s, members := range species {
+		for _, indi := range members {
+			shares[s] += (worst - indi.Fitness + 1) / float64(len(members))
+		}
+		sum += shares[s]
+	}
+
+	counts := make([]int, len(species))
+	remainders := make([]int, len(species))
+	given := 0
+	for s := range species {
+		exact := shares[s] / sum * float64(total)
+		counts[s] = int(exact)
+		given += counts[s]
+		shares[s] = exact - float64(counts[s])
+		remainders[s] = s
+	}
+
+	sort.SliceStable(remainders, func(i, j int) bool { return shares[remainders[i]] > shares[remainders[j]] })
+	for _, s := range remainders[:min(total-given, len(remainders))] {
+		counts[s]++
+	}
+
+	return counts
+}
+
+// tournament returns the fittest of NContestants members drawn at random.
+func (m *NEATModel) tournament(members eaopt.Individuals, rng *rand.Rand) eaopt.Individual {
+	best := members[rng.Intn(len(members))]
+	for range int(m.NContestants) - 1 {
+		if c := members[rng.Intn(len(members))]; c.Fitness < best.Fitness {
+			best = c
+		}
+	}
+
+	return best
+}
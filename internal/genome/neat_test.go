@@ -0,0 +1,364 @@
+package genome
+
+import (
+	"math"
+	"math/rand"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/MaxHalford/eaopt"
+)
+
+// newTestNEATGenome returns a NEAT genome that has been mutated for a while, so it has hidden nodes.
+func newTestNEATGenome(t *testing.T, seed int64) *NEATGenome {
+	t.Helper()
+	rng := rand.New(rand.NewSource(seed))
+	g := NewNEATGenome(rng, nil, NewInnovations(InputSize))
+	for range 200 {
+		g.Mutate(rng)
+	}
+
+	if g.HiddenNodes() == 0 {
+		t.Fatalf("mutations added no hidden nodes")
+	}
+
+	return g
+}
+
+func TestNEATNetwork_Forward(t *testing.T) {
+	t.Parallel()
+
+	// input 2 feeds hidden node h, which feeds output 5 along with input 0
+	h := InputSize + OutputSize
+	g := &NEATGenome{
+		Conns: []ConnGene{
+			{Innovation: 0, In: 0, Out: InputSize + 5, Weight: 0.5, Enabled: true},
+			{Innovation: 1, In: 2, Out: InputSize + 5, Weight: 9, Enabled: false},
+			{Innovation: 2, In: 2, Out: h, Weight: 2, Enabled: true},
+			{Innovation: 3, In: h, Out: InputSize + 5, Weight: -3, Enabled: true},
+		},
+	}
+	for o := range OutputSize {
+		g.Nodes = append(g.Nodes, NodeGene{ID: InputSize + o, Bias: float64(o)})
+	}
+	g.Nodes = append(g.Nodes, NodeGene{ID: h, Bias: 0.25})
+
+	n, err := NewNEATNetwork(g)
+	if err != nil {
+		t.Fatalf("NewNEATNetwork() error = %v", err)
+	}
+
+	inputs := make([]float64, InputSize)
+	inputs[0], inputs[2] = 1, 1
+	got := n.Forward(inputs)
+
+	for o := range OutputSize {
+		want := float64(o)
+		if o == 5 {
+			want += 0.5 - 3*math.Tanh(2+0.25)
+		}
+		if math.Abs(got[o]-want) > 1e-12 {
+			t.Errorf("Forward()[%d] = %v, want %v", o, got[o], want)
+		}
+	}
+}
+
+func TestNEATGenome_Mutate(t *testing.T) {
+	t.Parallel()
+	g := newTestNEATGenome(t, 1)
+
+	if err := g.Validate(); err != nil {
+		t.Fatalf("Validate() error after mutations = %v", err)
+	}
+
+	if _, err := NewNEATNetwork(g); err != nil {
+		t.Fatalf("NewNEATNetwork() error = %v", err)
+	}
+
+	// a genome without innovations only has its weights mutated
+	rng := rand.New(rand.NewSource(2))
+	still := NewNEATGenome(rng, nil, nil)
+	for range 100 {
+		still.Mutate(rng)
+	}
+	if still.HiddenNodes() != 0 || len(still.Conns) != InputSize*OutputSize {
+		t.Errorf("genome without innovations has %d hidden nodes and %d connections", still.HiddenNodes(), len(still.Conns))
+	}
+}
+
+func TestInnovations_Split(t *testing.T) {
+	t.Parallel()
+	innov := NewInnovations(InputSize)
+	a := NewNEATGenome(rand.New(rand.NewSource(0)), nil, innov)
+	b := NewNEATGenome(rand.New(rand.NewSource(1)), nil, innov)
+
+	// the same split in two genomes makes the same node
+	first, second := innov.Split(a, 7), innov.Split(b, 7)
+	if first != second {
+		t.Errorf("Split() = %d and %d, want the same node", first, second)
+	}
+
+	// a genome that already has the node gets a new one
+	a.Nodes = append(a.Nodes, NodeGene{ID: first})
+	if again := innov.Split(a, 7); again == first {
+		t.Errorf("Split() = %d again for a genome that already has it", again)
+	}
+
+	if innov.Conn(0, first) != innov.Conn(0, second) {
+		t.Errorf("Conn() numbered the same connection twice")
+	}
+}
+
+func TestNEATGenome_Validate(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		change func(g *NEATGenome)
+	}{
+		{name: "Unknown encoding", change: func(g *NEATGenome) { g.Encoding = "binary" }},
+		{name: "Missing output", change: func(g *NEATGenome) { g.Nodes = g.Nodes[1:] }},
+		{name: "Unsorted connections", change: func(g *NEATGenome) { g.Conns[0], g.Conns[1] = g.Conns[1], g.Conns[0] }},
+		{name: "Missing node", change: func(g *NEATGenome) { g.Conns[0].Out = 1000 }},
+		{
+			name: "Cycle",
+			change: func(g *NEATGenome) {
+				h := g.Nodes[OutputSize].ID
+				g.insertConn(ConnGene{Innovation: 1 << 20, In: InputSize, Out: h, Enabled: true})
+				g.insertConn(ConnGene{Innovation: 1<<20 + 1, In: h, Out: InputSize, Enabled: true})
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			g := newTestNEATGenome(t, 3)
+			tt.change(g)
+			if err := g.Validate(); err == nil {
+				t.Errorf("Validate() expected an error")
+			}
+		})
+	}
+}
+
+func TestCrossNEAT(t *testing.T) {
+	t.Parallel()
+	fitter := newTestNEATGenome(t, 4)
+	other := newTestNEATGenome(t, 5)
+	rng := rand.New(rand.NewSource(0))
+
+	child := crossNEAT(fitter, other, rng)
+	if err := child.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	if len(child.Nodes) != len(fitter.Nodes) || len(child.Conns) != len(fitter.Conns) {
+		t.Fatalf("child has %d nodes and %d connections, want the %d and %d of the fitter parent",
+			len(child.Nodes), len(child.Conns), len(fitter.Nodes), len(fitter.Conns))
+	}
+
+	fromOther := 0
+	for i, c := range child.Conns {
+		if c.Innovation != fitter.Conns[i].Innovation {
+			t.Fatalf("child connection %d has innovation %d, want %d", i, c.Innovation, fitter.Conns[i].Innovation)
+		}
+		if c.Weight != fitter.Conns[i].Weight {
+			fromOther++
+		}
+	}
+
+	if fromOther == 0 {
+		t.Errorf("child took no weights from the other parent")
+	}
+}
+
+func TestNEATGenome_Crossover(t *testing.T) {
+	t.Parallel()
+	weaker := newTestNEATGenome(t, 4)
+	fitter := newTestNEATGenome(t, 5)
+	weaker.fitness, weaker.evaluated = 150, true
+	fitter.fitness, fitter.evaluated = 100, true
+	want := fitter.Clone().(*NEATGenome)
+
+	// the receiver is the weaker parent, but both children take the structure of the fitter one
+	weaker.Crossover(fitter, rand.New(rand.NewSource(0)))
+	for _, child := range []*NEATGenome{weaker, fitter} {
+		if len(child.Nodes) != len(want.Nodes) || len(child.Conns) != len(want.Conns) {
+			t.Errorf("child has %d nodes and %d connections, want the %d and %d of the fitter parent",
+				len(child.Nodes), len(child.Conns), len(want.Nodes), len(want.Conns))
+		}
+	}
+}
+
+func TestNEATModel_Species(t *testing.T) {
+	t.Parallel()
+	model := &NEATModel{Threshold: 3, CrossRate: 0.5, NContestants: 2}
+	rng := rand.New(rand.NewSource(0))
+
+	// two genomes and their clones make two species, before any generation has been bred
+	var indis eaopt.Individuals
+	for seed := range int64(2) {
+		g := newTestNEATGenome(t, seed+10)
+		indis = append(indis, eaopt.NewIndividual(g, rng), eaopt.NewIndividual(g.Clone(), rng))
+	}
+
+	for range 2 {
+		if species, err := model.Species(indis); err != nil || species != 2 {
+			t.Errorf("Species() = %d, %v, want 2", species, err)
+		}
+	}
+
+	if len(model.representatives) != 0 {
+		t.Errorf("Species() changed the representatives")
+	}
+}
+
+func TestNEATGenome_Distance(t *testing.T) {
+	t.Parallel()
+	g := newTestNEATGenome(t, 6)
+
+	if d := g.Distance(g.Clone().(*NEATGenome)); d != 0 {
+		t.Errorf("Distance() to a clone = %v, want 0", d)
+	}
+
+	// a new node disables a connection and adds two
+	other := g.Clone().(*NEATGenome)
+	other.addNode(rand.New(rand.NewSource(0)))
+	if d := g.Distance(other); d != 2 {
+		t.Errorf("Distance() after adding a node = %v, want 2", d)
+	}
+	if g.Distance(other) != other.Distance(g) {
+		t.Errorf("Distance() isn't symmetric")
+	}
+}
+
+func TestShareOffspring(t *testing.T) {
+	t.Parallel()
+	species := []eaopt.Individuals{
+		{{Fitness: 100}, {Fitness: 100}},
+		{{Fitness: 150}},
+		{{Fitness: 50}, {Fitness: 150}, {Fitness: 100}},
+	}
+
+	counts := shareOffspring(species, 10)
+
+	total := 0
+	for _, c := range counts {
+		total += c
+	}
+	if total != 10 {
+		t.Errorf("shareOffspring() gave %d offspring, want 10", total)
+	}
+
+	// fitness is minimized, and species with the same mean fitness get the same share whatever their size
+	if want := []int{5, 0, 5}; !reflect.DeepEqual(counts, want) {
+		t.Errorf("shareOffspring() = %v, want %v", counts, want)
+	}
+}
+
+func TestNEATModel_Apply(t *testing.T) {
+	t.Parallel()
+	eval, err := NewEvaluation(2, StatMean, 1)
+	if err != nil {
+		t.Fatalf("NewEvaluation() error = %v", err)
+	}
+
+	model := &NEATModel{Threshold: 3, CrossRate: 0.5, NContestants: 2}
+	if err := model.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	config := eaopt.NewDefaultGAConfig()
+	config.NGenerations = 5
+	config.PopSize = 12
+	config.NPops = 1
+	config.Model = model
+	config.RNG = rand.New(rand.NewSource(0))
+
+	ga, err := config.NewGA()
+	if err != nil {
+		t.Fatalf("NewGA() error = %v", err)
+	}
+
+	factory := NewNEATFactory(nil, NewInnovations(InputSize)).WithEvaluation(eval)
+	if err := ga.Minimize(factory); err != nil {
+		t.Fatalf("Minimize() error = %v", err)
+	}
+
+	indis := ga.Populations[0].Individuals
+	if len(indis) != 12 {
+		t.Fatalf("population has %d individuals, want 12", len(indis))
+	}
+
+	for _, indi := range indis {
+		g, ok := indi.Genome.(*NEATGenome)
+		if !ok {
+			t.Fatalf("population holds a %T, want *NEATGenome", indi.Genome)
+		}
+		if g.Eval != eval {
+			t.Errorf("offspring lost the evaluation")
+		}
+		if err := g.Validate(); err != nil {
+			t.Errorf("Validate() error = %v", err)
+		}
+	}
+
+	if species, err := model.Species(indis); err != nil || species == 0 {
+		t.Errorf("Species() = %d, %v after training", species, err)
+	}
+}
+
+func TestNEATModel_Validate(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name  string
+		model NEATModel
+	}{
+		{name: "No threshold", model: NEATModel{CrossRate: 0.5, NContestants: 2}},
+		{name: "Cross rate", model: NEATModel{Threshold: 3, CrossRate: 2, NContestants: 2}},
+		{name: "No contestants", model: NEATModel{Threshold: 3, CrossRate: 0.5}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if err := tt.model.Validate(); err == nil {
+				t.Errorf("Validate() expected an error")
+			}
+		})
+	}
+}
+
+func TestSaveNEAT_LoadNetwork(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "neat.json")
+	g := newTestNEATGenome(t, 7)
+	info := TrainingInfo{Config: TrainingConfig{Kind: KindNEAT, SpeciesThreshold: 3}, Generation: 4, Fitness: 180}
+
+	if err := SaveNEAT(path, g, info); err != nil {
+		t.Fatalf("SaveNEAT() error = %v", err)
+	}
+
+	network, gotInfo, err := LoadNetwork(path)
+	if err != nil {
+		t.Fatalf("LoadNetwork() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(*gotInfo, info) {
+		t.Errorf("LoadNetwork() info = %+v, want %+v", *gotInfo, info)
+	}
+
+	want, err := NewNEATNetwork(g)
+	if err != nil {
+		t.Fatalf("NewNEATNetwork() error = %v", err)
+	}
+
+	inputs := make([]float64, InputSize)
+	inputs[1], inputs[20], inputs[36] = 1, 1, 1
+	if got, want := network.Forward(inputs), want.Forward(inputs); !reflect.DeepEqual(got, want) {
+		t.Errorf("loaded network outputs %v, want %v", got, want)
+	}
+
+	if _, _, err := Load(path); err == nil {
+		t.Errorf("Load() expected an error for a NEAT genome")
+	}
+}
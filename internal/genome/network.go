@@ -0,0 +1,191 @@
+package genome
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/iadams749/JumbleBerryFieldsBot/internal/game"
+	"github.com/iadams749/JumbleBerryFieldsBot/internal/player"
+)
+
+// A Network runs the forward pass of a genome directly on its weights and biases.
+// It gives the same outputs as the graph from BuildGraph, but doesn't allocate once it is built,
+// which makes it much faster for playing games. NEAT genomes are played with a Network too.
+//
+// A Network holds its own buffers, so it must not be used from more than one goroutine at a time.
+type Network struct {
+	net     forwarder
+	encoder Encoder
+	decoder Decoder
+	input   []float64
+}
+
+// A forwarder computes the outputs of a network from its inputs.
+// The returned slice is owned by the forwarder and is overwritten by the next call.
+type forwarder interface {
+	forward(inputs []float64) []float64
+}
+
+// denseLayers are the fully connected layers of a Genome, from the first hidden layer to the output layer.
+type denseLayers []denseLayer
+
+// denseLayer is one fully connected layer of a Network.
+type denseLayer struct {
+	in, out int
+
+	// weights is laid out like the (in, out) matrix BuildGraph multiplies by,
+	// so the weight from input a to output b is weights[a*out+b].
+	weights    []float64
+	biases     []float64
+	activation Activation
+
+	// activations holds the outputs of the layer from the last forward pass.
+	activations []float64
+}
+
+// NewNetwork builds a Network from the genome.
+// The network copies the weights and biases, so later changes to the genome don't affect it.
+func NewNetwork(g *Genome) (*Network, error) {
+	if err := g.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Validate has already checked the encoding and decoding
+	enc, _ := g.Encoding.NewEncoder()
+	dec, _ := g.Decoding.NewDecoder()
+
+	var layers denseLayers
+	acts := g.layerActivations()
+	for i := range g.Weights {
+		layers = append(layers, denseLayer{
+			in:          len(g.Weights[i][0]),
+			out:         len(g.Weights[i]),
+			weights:     flatten2D(g.Weights[i]),
+			biases:      append([]float64{}, g.Biases[i]...),
+			activation:  acts[i],
+			activations: make([]float64, len(g.Biases[i])),
+		})
+	}
+
+	return &Network{net: layers, encoder: enc, decoder: dec, input: make([]float64, enc.Size())}, nil
+}
+
+// Forward runs the network on the inputs and returns its outputs.
+// The returned slice is owned by the network and is overwritten by the next call.
+func (n *Network) Forward(inputs []float64) []float64 {
+	return n.net.forward(inputs)
+}
+
+// forward runs every layer in turn.
+func (layers denseLayers) forward(inputs []float64) []float64 {
+	x := inputs
+	for i := range layers {
+		x = layers[i].forward(x)
+	}
+
+	return x
+}
+
+// forward computes x * W + B for the layer, and applies its activation.
+func (l *denseLayer) forward(x []float64) []float64 {
+	z := l.activations
+	copy(z, l.biases)
+
+	for a, xa := range x[:l.in] {
+		// the inputs are mostly one-hot encoded, so most of them can be skipped
+		if xa == 0 {
+			continue
+		}
+
+		row := l.weights[a*l.out : (a+1)*l.out]
+		for b, w := range row {
+			z[b] += xa * w
+		}
+	}
+
+	l.activation.apply(z)
+
+	return z
+}
+
+// MakeMove runs the network on the game state and applies the move it chooses.
+func (n *Network) MakeMove(gs *game.GameState) error {
+	action, err := n.ChooseAction(gs)
+	if err != nil {
+		return err
+	}
+
+	return gs.Apply(action)
+}
+
+// A GameResult is how a game played by a Network went.
+type GameResult struct {
+	// Score is the final score, or the score when the game stalled.
+	Score int
+
+	// Invalid counts the decisions that were illegal or did nothing:
+	// the network preferred an illegal action over the one it took, or re-rolled while keeping every jar.
+	Invalid int
+}
+
+// Play plays a full game using the network, drawing every roll from r, and returns how it went.
+// If r is nil, a randomly seeded Roller is used.
+//
+// An illegal action from the decoder counts as an invalid decision and is skipped. Every legal action uses up
+// a roll or a round, so Play gives up with player.ErrStalled if the game isn't over after the most actions a game
+// can take. The built-in decoders mask out illegal actions, so only a custom Decoder can make a game stall.
+func (n *Network) Play(r game.Roller) (GameResult, error) {
+	gs := game.NewGameWithRoller(game.DefaultRules(), r)
+	gs.RollJars()
+
+	var result GameResult
+	keepAll := uint(1)<<len(gs.Jars) - 1
+
+	for steps := gs.MaxActionsLeft(); !gs.IsOver(); steps-- {
+		if steps == 0 {
+			result.Score = gs.Score
+			return result, fmt.Errorf("%w in round %d", player.ErrStalled, gs.RoundsCompleted+1)
+		}
+
+		n.encoder.Encode(gs, n.input)
+		action, skipped, err := n.decoder.Decode(gs, n.Forward(n.input))
+		if err != nil {
+			return result, err
+		}
+
+		if !gs.IsLegal(action) {
+			result.Invalid++
+			continue
+		}
+
+		if len(skipped) > 0 || (action.Kind == game.Reroll && action.Keep == keepAll) {
+			result.Invalid++
+		}
+
+		if err := gs.Apply(action); err != nil {
+			return result, err
+		}
+	}
+
+	result.Score = gs.Score
+	return result, nil
+}
+
+// PlayGame plays a full game using the network, drawing every roll from r, and returns the final score.
+// If r is nil, a randomly seeded Roller is used. A game that stalls scores what it had when it stalled.
+func (n *Network) PlayGame(r game.Roller) int {
+	result, err := n.Play(r)
+	if err != nil && !errors.Is(err, player.ErrStalled) {
+		panic(err.Error())
+	}
+
+	return result.Score
+}
+
+// ChooseAction returns the action the network would take in the game state, without applying it.
+func (n *Network) ChooseAction(gs *game.GameState) (game.Action, error) {
+	n.encoder.Encode(gs, n.input)
+	action, _, err := n.decoder.Decode(gs, n.Forward(n.input))
+
+	return action, err
+}
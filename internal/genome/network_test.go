@@ -0,0 +1,324 @@
+package genome
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/iadams749/JumbleBerryFieldsBot/internal/game"
+	"github.com/iadams749/JumbleBerryFieldsBot/internal/player"
+	"gorgonia.org/gorgonia"
+	"gorgonia.org/tensor"
+)
+
+// graphOutputs runs the graph of the genome on the inputs.
+func graphOutputs(t testing.TB, g *Genome, inputs []float64) []float64 {
+	t.Helper()
+	graph, input, output, err := g.BuildGraph()
+	if err != nil {
+		t.Fatalf("BuildGraph() error = %v", err)
+	}
+
+	vm := gorgonia.NewTapeMachine(graph)
+	defer vm.Close()
+
+	if err := gorgonia.Let(input, tensor.New(tensor.WithBacking(append([]float64{}, inputs...)), tensor.WithShape(1, len(inputs)))); err != nil {
+		t.Fatalf("Let() error = %v", err)
+	}
+
+	if err := vm.RunAll(); err != nil {
+		t.Fatalf("RunAll() error = %v", err)
+	}
+
+	return append([]float64{}, output.Value().Data().([]float64)...)
+}
+
+func TestNetwork_Forward(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name             string
+		hiddenLayerSizes []int
+	}{
+		{name: "no hidden layers", hiddenLayerSizes: nil},
+		{name: "one hidden layer", hiddenLayerSizes: []int{8}},
+		{name: "two hidden layers", hiddenLayerSizes: []int{16, 4}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			rng := rand.New(rand.NewSource(1))
+			g := NewGenome(rng, tt.hiddenLayerSizes, nil)
+
+			n, err := NewNetwork(g)
+			if err != nil {
+				t.Fatalf("NewNetwork() error = %v", err)
+			}
+
+			for range 5 {
+				// dense random inputs exercise every weight, not just the one-hot ones
+				inputs := make([]float64, InputSize)
+				for i := range inputs {
+					inputs[i] = rng.NormFloat64()
+				}
+
+				want := graphOutputs(t, g, inputs)
+				got := n.Forward(inputs)
+
+				for i := range want {
+					if math.Abs(got[i]-want[i]) > 1e-9 {
+						t.Fatalf("Forward()[%d] = %v, want %v", i, got[i], want[i])
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestNewNetwork_Invalid(t *testing.T) {
+	t.Parallel()
+	g := NewGenome(rand.New(rand.NewSource(0)), []int{4}, nil)
+	g.Biases[0] = g.Biases[0][1:]
+
+	if _, err := NewNetwork(g); err == nil {
+		t.Errorf("NewNetwork() expected an error for a malformed genome")
+	}
+}
+
+func TestNetwork_PlayGame(t *testing.T) {
+	t.Parallel()
+	g := NewGenome(rand.New(rand.NewSource(2)), []int{8}, nil)
+
+	n, err := NewNetwork(g)
+	if err != nil {
+		t.Fatalf("NewNetwork() error = %v", err)
+	}
+
+	graph, input, output, err := g.BuildGraph()
+	if err != nil {
+		t.Fatalf("BuildGraph() error = %v", err)
+	}
+
+	for seed := range int64(5) {
+		want := PlayGameWithRoller(graph, input, output, game.NewSeededRoller(seed))
+		if got := n.PlayGame(game.NewSeededRoller(seed)); got != want {
+			t.Errorf("PlayGame() with seed %d = %d, want %d", seed, got, want)
+		}
+	}
+}
+
+func TestNetwork_Play(t *testing.T) {
+	t.Parallel()
+	g := NewGenome(rand.New(rand.NewSource(2)), []int{8}, nil)
+
+	n, err := NewNetwork(g)
+	if err != nil {
+		t.Fatalf("NewNetwork() error = %v", err)
+	}
+
+	for seed := range int64(5) {
+		result, err := n.Play(game.NewSeededRoller(seed))
+		if err != nil {
+			t.Fatalf("Play() error = %v", err)
+		}
+
+		if want := n.PlayGame(game.NewSeededRoller(seed)); result.Score != want {
+			t.Errorf("Play() with seed %d scored %d, PlayGame() = %d", seed, result.Score, want)
+		}
+
+		// tracing the same game shows the same invalid decisions
+		gs := game.NewGameWithRoller(game.DefaultRules(), game.NewSeededRoller(seed))
+		gs.RollJars()
+
+		invalid := 0
+		for !gs.IsOver() {
+			step, err := n.TraceMove(gs)
+			if err != nil {
+				t.Fatalf("TraceMove() error = %v", err)
+			}
+			if len(step.Skipped) > 0 || step.Action == "reroll keeping [0 1 2 3 4]" {
+				invalid++
+			}
+		}
+
+		if result.Invalid != invalid {
+			t.Errorf("Play() with seed %d made %d invalid decisions, want %d", seed, result.Invalid, invalid)
+		}
+	}
+}
+
+// stuckDecoder re-rolls every jar it is asked about, even once the turn is out of rolls.
+type stuckDecoder struct{}
+
+func (stuckDecoder) Decoding() Decoding { return "stuck" }
+
+func (stuckDecoder) Decode(gs *game.GameState, outputs []float64) (game.Action, []SkippedAction, error) {
+	return game.Action{Kind: game.Reroll, Keep: 1<<len(gs.Jars) - 1}, nil, nil
+}
+
+func TestNetwork_PlayStalls(t *testing.T) {
+	t.Parallel()
+	n, err := NewNetwork(NewGenome(rand.New(rand.NewSource(2)), []int{8}, nil))
+	if err != nil {
+		t.Fatalf("NewNetwork() error = %v", err)
+	}
+	n.decoder = stuckDecoder{}
+
+	// every decision is invalid: two re-rolls that keep every jar, then re-rolls without any rolls left
+	gs := game.NewGameWithRoller(game.DefaultRules(), game.NewSeededRoller(0))
+	gs.RollJars()
+	budget := gs.MaxActionsLeft()
+
+	result, err := n.Play(game.NewSeededRoller(0))
+	if !errors.Is(err, player.ErrStalled) {
+		t.Fatalf("Play() error = %v, want %v", err, player.ErrStalled)
+	}
+	if want := (GameResult{Score: 0, Invalid: budget}); result != want {
+		t.Errorf("Play() = %+v, want %+v", result, want)
+	}
+
+	e, err := NewEvaluation(2, StatMean, 1)
+	if err != nil {
+		t.Fatalf("NewEvaluation() error = %v", err)
+	}
+	e.Penalty = 0.5
+
+	score, err := e.Score(n.Play)
+	if err != nil {
+		t.Fatalf("Score() error = %v", err)
+	}
+	if want := -0.5 * float64(budget); score != want {
+		t.Errorf("Score() = %v, want %v", score, want)
+	}
+	if e.Stalled() != 1 {
+		t.Errorf("Stalled() = %d, want 1", e.Stalled())
+	}
+}
+
+func TestNetwork_ForwardAllocs(t *testing.T) {
+	n, err := NewNetwork(NewGenome(rand.New(rand.NewSource(0)), []int{16}, nil))
+	if err != nil {
+		t.Fatalf("NewNetwork() error = %v", err)
+	}
+
+	inputs := make([]float64, InputSize)
+	inputs[0] = 1
+
+	if allocs := testing.AllocsPerRun(100, func() { n.Forward(inputs) }); allocs != 0 {
+		t.Errorf("Forward() allocates %v times per run, want 0", allocs)
+	}
+}
+
+func TestNetwork_PlayAllocs(t *testing.T) {
+	// with every output equal, the network scores the first open category each turn and never makes an invalid
+	// decision, so the only allocations left are the game's own
+	g := NewGenome(rand.New(rand.NewSource(0)), []int{16}, nil)
+	for i := range g.Weights {
+		for _, row := range g.Weights[i] {
+			clear(row)
+		}
+		clear(g.Biases[i])
+	}
+
+	n, err := NewNetwork(g)
+	if err != nil {
+		t.Fatalf("NewNetwork() error = %v", err)
+	}
+
+	gs := game.NewGameWithRoller(nil, game.NewSeededRoller(0))
+	gs.RollJars()
+	if allocs := testing.AllocsPerRun(100, func() { n.ChooseAction(gs) }); allocs != 0 {
+		t.Errorf("ChooseAction() allocates %v times per run, want 0", allocs)
+	}
+
+	if result, err := n.Play(game.NewSeededRoller(1)); err != nil || result.Invalid != 0 {
+		t.Fatalf("Play() = %+v, %v, want no invalid decisions", result, err)
+	}
+
+	want := testing.AllocsPerRun(20, func() {
+		gs := game.NewGameWithRoller(game.DefaultRules(), game.NewSeededRoller(1))
+		gs.RollJars()
+		for !gs.IsOver() {
+			for id := range game.CategoryID(game.NumCategories) {
+				if a := game.NewScore(id); gs.IsLegal(a) {
+					gs.Apply(a)
+					break
+				}
+			}
+		}
+	})
+
+	if allocs := testing.AllocsPerRun(20, func() { n.Play(game.NewSeededRoller(1)) }); allocs != want {
+		t.Errorf("Play() allocates %v times per game, want the %v of the game itself", allocs, want)
+	}
+}
+
+// benchmarkGenome is the size of network used when training by default.
+func benchmarkGenome() *Genome {
+	return NewGenome(rand.New(rand.NewSource(0)), DefaultTrainingConfig().HiddenLayerSizes, nil)
+}
+
+func BenchmarkForward_Graph(b *testing.B) {
+	graph, input, output, err := benchmarkGenome().BuildGraph()
+	if err != nil {
+		b.Fatal(err)
+	}
+	gs := game.NewGameWithRoller(nil, game.NewSeededRoller(0))
+	gs.RollJars()
+
+	for b.Loop() {
+		vm := gorgonia.NewTapeMachine(graph)
+		if err := gorgonia.Let(input, TranslateGameState(gs)); err != nil {
+			b.Fatal(err)
+		}
+		if err := vm.RunAll(); err != nil {
+			b.Fatal(err)
+		}
+		_ = output.Value()
+		vm.Close()
+	}
+}
+
+func BenchmarkForward_Network(b *testing.B) {
+	n, err := NewNetwork(benchmarkGenome())
+	if err != nil {
+		b.Fatal(err)
+	}
+	gs := game.NewGameWithRoller(nil, game.NewSeededRoller(0))
+	gs.RollJars()
+	inputs := make([]float64, InputSize)
+
+	b.ReportAllocs()
+	for b.Loop() {
+		OneHotEncoder{}.Encode(gs, inputs)
+		n.Forward(inputs)
+	}
+}
+
+func BenchmarkEvaluate_Graph(b *testing.B) {
+	g := benchmarkGenome()
+	var seed int64
+
+	for b.Loop() {
+		graph, input, output, err := g.BuildGraph()
+		if err != nil {
+			b.Fatal(err)
+		}
+		PlayGameWithRoller(graph, input, output, game.NewSeededRoller(seed))
+		seed++
+	}
+}
+
+func BenchmarkEvaluate_Network(b *testing.B) {
+	g := benchmarkGenome()
+	var seed int64
+
+	for b.Loop() {
+		n, err := NewNetwork(g)
+		if err != nil {
+			b.Fatal(err)
+		}
+		n.PlayGame(game.NewSeededRoller(seed))
+		seed++
+	}
+}
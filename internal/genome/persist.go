@@ -0,0 +1,333 @@
+package genome
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// FileVersion is the version of the saved genome format written by Save.
+const FileVersion = 1
+
+// TrainingConfig holds the hyperparameters a genome was trained with.
+type TrainingConfig struct {
+	Generations      int     `json:"generations"`
+	PopSize          int     `json:"popSize"`
+	HiddenLayerSizes []int   `json:"hiddenLayerSizes"`
+	MutRate          float64 `json:"mutRate"`
+	CrossRate        float64 `json:"crossRate"`
+	NContestants     int     `json:"nContestants"`
+
+	// Games is how many games each genome plays per generation, and Statistic how their scores are combined.
+	Games     int       `json:"games"`
+	Statistic Statistic `json:"statistic"`
+
+	// Seed seeds the random number generator of the GA. A seed of 0 picks a random seed.
+	Seed int64 `json:"seed"`
+
+	// Encoding is how game states are turned into the inputs of the networks.
+	Encoding Encoding `json:"encoding"`
+
+	// Decoding is how the outputs of the networks are turned into actions.
+	Decoding Decoding `json:"decoding"`
+
+	// Penalty is the fitness penalty for every invalid decision per game, see Evaluation.
+	Penalty float64 `json:"penalty"`
+
+	// Activations holds the activation of each hidden layer and then the output layer.
+	// If it is empty, DefaultActivations is used.
+	Activations []Activation `json:"activations"`
+
+	// Kind is the kind of genome that is trained. HiddenLayerSizes, Activations and MutRate only apply to KindDense.
+	Kind GenomeKind `json:"kind"`
+
+	// SpeciesThreshold is the compatibility distance under which NEAT genomes share a species, see NEATModel.
+	SpeciesThreshold float64 `json:"speciesThreshold"`
+}
+
+// DefaultTrainingConfig returns the hyperparameters used when none are provided.
+func DefaultTrainingConfig() TrainingConfig {
+	return TrainingConfig{
+		Generations:      1000,
+		PopSize:          100,
+		HiddenLayerSizes: []int{128, 128},
+		MutRate:          0.2,
+		CrossRate:        0.7,
+		NContestants:     3,
+		Games:            10,
+		Statistic:        StatMean,
+		Encoding:         EncodingOneHot,
+		Decoding:         DecodingArgmax,
+		Penalty:          1,
+		Kind:             KindDense,
+		SpeciesThreshold: 3,
+	}
+}
+
+// LoadTrainingConfig reads a TrainingConfig from the JSON file at path.
+// Any fields that are missing from the file keep their value from DefaultTrainingConfig.
+func LoadTrainingConfig(path string) (TrainingConfig, error) {
+	config := DefaultTrainingConfig()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return config, fmt.Errorf("error reading training config: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &config); err != nil {
+		return config, fmt.Errorf("error decoding training config: %w", err)
+	}
+
+	return config, nil
+}
+
+// TrainingInfo describes where a saved genome came from.
+type TrainingInfo struct {
+	// Config is the training configuration used to produce the genome.
+	Config TrainingConfig `json:"config"`
+
+	// Generation is the generation the genome was saved at.
+	Generation uint `json:"generation"`
+
+	// Fitness is the fitness of the genome when it was saved. Lower is better.
+	Fitness float64 `json:"fitness"`
+}
+
+// A GenomeKind names the kind of genome that is trained or saved.
+type GenomeKind string
+
+const (
+	// KindDense is a Genome, whose network has the fixed hidden layers it was created with.
+	KindDense GenomeKind = "dense"
+
+	// KindNEAT is a NEATGenome, whose network evolves its structure.
+	KindNEAT GenomeKind = "neat"
+)
+
+// envelope is the versioned format genomes are saved in. It holds either a Genome or a NEATGenome.
+type envelope struct {
+	Version          int          `json:"version"`
+	Kind             GenomeKind   `json:"kind,omitempty"`
+	Encoding         Encoding     `json:"encoding"`
+	Decoding         Decoding     `json:"decoding"`
+	InputSize        int          `json:"inputSize"`
+	OutputSize       int          `json:"outputSize"`
+	HiddenLayerSizes []int        `json:"hiddenLayerSizes,omitempty"`
+	Activations      []Activation `json:"activations,omitempty"`
+	Training         TrainingInfo `json:"training"`
+	Genome           *Genome      `json:"genome,omitempty"`
+	NEAT             *NEATGenome  `json:"neat,omitempty"`
+}
+
+// Save writes the genome and its training info to the JSON file at path.
+// The file is written to a temporary file first so an existing file is never left half written.
+func Save(path string, g *Genome, info TrainingInfo) error {
+	enc, err := g.Encoding.NewEncoder()
+	if err != nil {
+		return err
+	}
+
+	dec, err := g.Decoding.NewDecoder()
+	if err != nil {
+		return err
+	}
+
+	return writeEnvelope(path, envelope{
+		Version:          FileVersion,
+		Kind:             KindDense,
+		Encoding:         enc.Encoding(),
+		Decoding:         dec.Decoding(),
+		InputSize:        enc.Size(),
+		OutputSize:       OutputSize,
+		HiddenLayerSizes: g.HiddenLayerSizes,
+		Activations:      g.layerActivations(),
+		Training:         info,
+		Genome:           g,
+	})
+}
+
+// SaveNEAT writes the NEAT genome and its training info to the JSON file at path, like Save.
+func SaveNEAT(path string, g *NEATGenome, info TrainingInfo) error {
+	enc, err := g.Encoding.NewEncoder()
+	if err != nil {
+		return err
+	}
+
+	dec, err := g.Decoding.NewDecoder()
+	if err != nil {
+		return err
+	}
+
+	return writeEnvelope(path, envelope{
+		Version:    FileVersion,
+		Kind:       KindNEAT,
+		Encoding:   enc.Encoding(),
+		Decoding:   dec.Decoding(),
+		InputSize:  enc.Size(),
+		OutputSize: OutputSize,
+		Training:   info,
+		NEAT:       g,
+	})
+}
+
+// writeEnvelope encodes the envelope and writes it to path.
+func writeEnvelope(path string, env envelope) error {
+	data, err := json.MarshalIndent(env, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding genome: %w", err)
+	}
+
+	return writeFileAtomic(path, data)
+}
+
+// Load reads a genome saved with Save from path.
+// The shape of the genome is validated so it is safe to build a graph from it.
+func Load(path string) (*Genome, *TrainingInfo, error) {
+	env, err := readEnvelope(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if env.Genome == nil {
+		return nil, nil, fmt.Errorf("genome file holds a NEAT genome, which can only be loaded with LoadNetwork")
+	}
+
+	return env.Genome, &env.Training, nil
+}
+
+// LoadNetwork reads a genome saved with Save or SaveNEAT from path and builds its network.
+func LoadNetwork(path string) (*Network, *TrainingInfo, error) {
+	env, err := readEnvelope(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var network *Network
+	if env.NEAT != nil {
+		network, err = NewNEATNetwork(env.NEAT)
+	} else {
+		network, err = NewNetwork(env.Genome)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return network, &env.Training, nil
+}
+
+// readEnvelope reads the envelope saved at path, and validates the one genome it holds.
+func readEnvelope(path string) (*envelope, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading genome: %w", err)
+	}
+
+	var env envelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, fmt.Errorf("error decoding genome: %w", err)
+	}
+
+	if env.Version != FileVersion {
+		return nil, fmt.Errorf("unsupported genome file version %d", env.Version)
+	}
+
+	var encoding Encoding
+	switch {
+	case env.Genome != nil && env.NEAT != nil:
+		return nil, fmt.Errorf("genome file holds two genomes")
+	case env.Genome != nil:
+		encoding = env.Genome.Encoding
+	case env.NEAT != nil:
+		encoding = env.NEAT.Encoding
+	default:
+		return nil, fmt.Errorf("genome file has no genome")
+	}
+
+	enc, err := encoding.NewEncoder()
+	if err != nil {
+		return nil, err
+	}
+
+	if env.InputSize != enc.Size() || env.OutputSize != OutputSize {
+		return nil, fmt.Errorf("genome has %d inputs and %d outputs, expected %d and %d", env.InputSize, env.OutputSize, enc.Size(), OutputSize)
+	}
+
+	if env.Genome != nil {
+		err = env.Genome.Validate()
+	} else {
+		err = env.NEAT.Validate()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &env, nil
+}
+
+// Validate returns an error if the encoding, decoding or activations are unknown,
+// or the weights and biases don't match the hidden layer sizes.
+func (g *Genome) Validate() error {
+	enc, err := g.Encoding.NewEncoder()
+	if err != nil {
+		return fmt.Errorf("invalid genome: %w", err)
+	}
+
+	if _, err := g.Decoding.NewDecoder(); err != nil {
+		return fmt.Errorf("invalid genome: %w", err)
+	}
+
+	if err := CheckActivations(g.layerActivations(), len(g.HiddenLayerSizes)+1); err != nil {
+		return fmt.Errorf("invalid genome: %w", err)
+	}
+
+	sizes := []int{enc.Size()}
+	sizes = append(sizes, g.HiddenLayerSizes...)
+	sizes = append(sizes, OutputSize)
+
+	if len(g.Weights) != len(sizes)-1 || len(g.Biases) != len(sizes)-1 {
+		return fmt.Errorf("invalid genome: expected %d layers, got %d weights and %d biases", len(sizes)-1, len(g.Weights), len(g.Biases))
+	}
+
+	for i := 1; i < len(sizes); i++ {
+		if len(g.Biases[i-1]) != sizes[i] {
+			return fmt.Errorf("invalid genome: layer %d has %d biases, expected %d", i-1, len(g.Biases[i-1]), sizes[i])
+		}
+
+		if len(g.Weights[i-1]) != sizes[i] {
+			return fmt.Errorf("invalid genome: layer %d has %d weight rows, expected %d", i-1, len(g.Weights[i-1]), sizes[i])
+		}
+
+		for _, row := range g.Weights[i-1] {
+			if len(row) != sizes[i-1] {
+				return fmt.Errorf("invalid genome: layer %d has a weight row of length %d, expected %d", i-1, len(row), sizes[i-1])
+			}
+		}
+	}
+
+	return nil
+}
+
+// writeFileAtomic writes data to a temporary file next to path and renames it over path.
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("error creating temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error writing %s: %w", path, err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("error writing %s: %w", path, err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("error writing %s: %w", path, err)
+	}
+
+	return nil
+}
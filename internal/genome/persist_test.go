@@ -0,0 +1,99 @@
+package genome
+
+import (
+	"math/rand"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSaveLoad(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "genome.json")
+	g := NewGenome(rand.New(rand.NewSource(0)), []int{4, 3}, nil)
+	info := TrainingInfo{
+		Config: TrainingConfig{
+			Generations:      10,
+			PopSize:          20,
+			HiddenLayerSizes: []int{4, 3},
+			MutRate:          0.2,
+			CrossRate:        0.7,
+			NContestants:     3,
+		},
+		Generation: 5,
+		Fitness:    120.5,
+	}
+
+	if err := Save(path, g, info); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	got, gotInfo, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(got, g) {
+		t.Errorf("loaded genome does not match saved genome")
+	}
+
+	if !reflect.DeepEqual(*gotInfo, info) {
+		t.Errorf("Load() info = %+v, want %+v", *gotInfo, info)
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		data string
+	}{
+		{
+			name: "Not JSON",
+			data: `not json`,
+		},
+		{
+			name: "Wrong version",
+			data: `{"version": 99, "inputSize": 37, "outputSize": 15, "genome": {}}`,
+		},
+		{
+			name: "Wrong input size",
+			data: `{"version": 1, "inputSize": 36, "outputSize": 15, "genome": {}}`,
+		},
+		{
+			name: "Missing genome",
+			data: `{"version": 1, "inputSize": 37, "outputSize": 15}`,
+		},
+		{
+			name: "Missing layers",
+			data: `{"version": 1, "inputSize": 37, "outputSize": 15, "genome": {"hiddenLayerSizes": [2], "weights": [], "biases": []}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			path := filepath.Join(t.TempDir(), "genome.json")
+			if err := os.WriteFile(path, []byte(tt.data), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, _, err := Load(path); err == nil {
+				t.Errorf("expected error but got nil")
+			}
+		})
+	}
+}
+
+func TestGenome_Validate(t *testing.T) {
+	t.Parallel()
+	g := NewGenome(rand.New(rand.NewSource(0)), []int{4}, nil)
+	if err := g.Validate(); err != nil {
+		t.Fatalf("expected nil error but got %v", err)
+	}
+
+	g.Weights[1][0] = g.Weights[1][0][:2]
+	if err := g.Validate(); err == nil {
+		t.Errorf("expected error for short weight row but got nil")
+	}
+}
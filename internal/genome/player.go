@@ -1,37 +1,93 @@
 package genome
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/iadams749/JumbleBerryFieldsBot/internal/game"
+	"github.com/iadams749/JumbleBerryFieldsBot/internal/player"
 	"gorgonia.org/gorgonia"
+	"gorgonia.org/tensor"
+)
+
+// Both ways of running a genome can play games.
+var (
+	_ player.Player = GraphPlayer{}
+	_ player.Player = (*Network)(nil)
 )
 
+// A GraphPlayer is a player.Player that runs a network built by BuildGraph.
+// A Network plays the same moves much faster, but the graph is what training builds.
+type GraphPlayer struct {
+	Graph  *gorgonia.ExprGraph
+	Input  *gorgonia.Node
+	Output *gorgonia.Node
+
+	// Encoder writes the inputs the network was built for. The default OneHotEncoder is used if it is nil.
+	Encoder Encoder
+
+	// Decoder turns the outputs into actions. The default ArgmaxDecoder is used if it is nil.
+	Decoder Decoder
+}
+
+// ChooseAction runs the network on the game state and returns the action it chooses, without applying it.
+func (p GraphPlayer) ChooseAction(gs *game.GameState) (game.Action, error) {
+	// Create VM to run computation
+	vm := gorgonia.NewTapeMachine(p.Graph)
+	defer vm.Close()
+
+	// Assign the input generated from the game state to the input node
+	enc := p.Encoder
+	if enc == nil {
+		enc = OneHotEncoder{}
+	}
+
+	if err := gorgonia.Let(p.Input, encodeTensor(enc, gs)); err != nil {
+		return game.Action{}, err
+	}
+
+	// Run the computation graph
+	if err := vm.RunAll(); err != nil {
+		return game.Action{}, err
+	}
+
+	dense, ok := p.Output.Value().(*tensor.Dense)
+	if !ok {
+		return game.Action{}, fmt.Errorf("GraphPlayer: expected a *tensor.Dense, got %T", p.Output.Value())
+	}
+
+	dec := p.Decoder
+	if dec == nil {
+		dec = ArgmaxDecoder{}
+	}
+
+	action, _, err := dec.Decode(gs, dense.Data().([]float64))
+	return action, err
+}
+
+// PlayGameFromGraph plays a full game with random dice using the network and returns the final score.
+// A game that stalls scores what it had when it stalled.
 func PlayGameFromGraph(g *gorgonia.ExprGraph, input *gorgonia.Node, output *gorgonia.Node) int {
-	gs := game.NewGame()
-	gs.RollJars()
-
-	for gs.RoundsCompleted < 9 {
-		// Create VM to run computation
-		vm := gorgonia.NewTapeMachine(g)
-		defer vm.Close()
-
-		// Generating the input from the game state
-		i := TranslateGameState(gs)
-
-		// Assign input data to the input node
-		if err := gorgonia.Let(input, i); err != nil {
-			panic(err.Error())
-		}
-
-		// Run the computation graph
-		if err := vm.RunAll(); err != nil {
-			panic(err.Error())
-		}
-
-		// Doing the move based off of the output
-		if err := DoMoveFromTensor(gs, output); err != nil {
-			panic(err.Error())
-		}
+	return PlayGameWithRoller(g, input, output, nil)
+}
+
+// PlayGameWithRoller plays a full game using the network, drawing every roll from r, and returns the final score.
+// If r is nil, a randomly seeded Roller is used. A game that stalls scores what it had when it stalled.
+func PlayGameWithRoller(g *gorgonia.ExprGraph, input *gorgonia.Node, output *gorgonia.Node, r game.Roller) int {
+	score, err := player.Play(GraphPlayer{Graph: g, Input: input, Output: output}, r)
+	if err != nil && !errors.Is(err, player.ErrStalled) {
+		panic(err.Error())
+	}
+
+	return score
+}
+
+// MakeMove runs the network on the game state and applies the move it chooses.
+func MakeMove(gs *game.GameState, g *gorgonia.ExprGraph, input *gorgonia.Node, output *gorgonia.Node) error {
+	action, err := GraphPlayer{Graph: g, Input: input, Output: output}.ChooseAction(gs)
+	if err != nil {
+		return err
 	}
 
-	return gs.Score
+	return gs.Apply(action)
 }
@@ -0,0 +1,117 @@
+package genome
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/iadams749/JumbleBerryFieldsBot/internal/game"
+)
+
+// OutputLabels names each output of the network.
+var OutputLabels = [OutputSize]string{
+	"lock jar 1", "lock jar 2", "lock jar 3", "lock jar 4", "lock jar 5",
+	"score Jumbleberry", "score Sugarberry", "score Pickleberry", "score Moonberry",
+	"score Three of a Kind", "score Four of a Kind", "score Five of a Kind", "score Mixed Basket", "score Free Roll",
+	"reroll",
+}
+
+// A LabelledOutput is one raw output of the network.
+type LabelledOutput struct {
+	Label string  `json:"label"`
+	Value float64 `json:"value"`
+}
+
+// A SkippedStep is a preferred action that was skipped because it isn't legal.
+type SkippedStep struct {
+	Action string `json:"action"`
+	Reason string `json:"reason"`
+}
+
+// A Step records one decision the network made, and the state of the game it made it in.
+type Step struct {
+	Round     int      `json:"round"`
+	RollsLeft int      `json:"rollsLeft"`
+	Berries   []string `json:"berries"`
+	Locked    []bool   `json:"locked"`
+
+	Outputs []LabelledOutput `json:"outputs"`
+	Skipped []SkippedStep    `json:"skipped,omitempty"`
+	Action  string           `json:"action"`
+
+	// Points is what the action scored, and Score the total score after it.
+	Points int `json:"points"`
+	Score  int `json:"score"`
+}
+
+// A Trace records every decision made in a game.
+type Trace struct {
+	Steps      []Step `json:"steps"`
+	FinalScore int    `json:"finalScore"`
+}
+
+// TraceMove runs the network on the game state, applies the move it chooses, and returns a record of the decision.
+func (n *Network) TraceMove(gs *game.GameState) (Step, error) {
+	step := Step{
+		Round:     gs.RoundsCompleted + 1,
+		RollsLeft: gs.RollsLeftInTurn,
+		Score:     gs.Score,
+	}
+
+	for _, jar := range gs.Jars {
+		step.Berries = append(step.Berries, jar.Berry.Name())
+		step.Locked = append(step.Locked, jar.Locked)
+	}
+
+	n.encoder.Encode(gs, n.input)
+	outputs := n.Forward(n.input)
+	for i, value := range outputs {
+		step.Outputs = append(step.Outputs, LabelledOutput{Label: OutputLabels[i], Value: value})
+	}
+
+	action, skipped, err := n.decoder.Decode(gs, outputs)
+	for _, s := range skipped {
+		step.Skipped = append(step.Skipped, SkippedStep{Action: s.Action.String(), Reason: s.Reason.Error()})
+	}
+	if err != nil {
+		return step, err
+	}
+
+	step.Action = action.String()
+	before := gs.Score
+	if err := gs.Apply(action); err != nil {
+		return step, err
+	}
+	step.Points = gs.Score - before
+	step.Score = gs.Score
+
+	return step, nil
+}
+
+// String renders the outputs, skipped actions and chosen action of the step for the terminal.
+func (s Step) String() string {
+	var b strings.Builder
+
+	b.WriteString("Outputs:\n")
+	for _, out := range s.Outputs {
+		fmt.Fprintf(&b, "  %-22s %8.3f\n", out.Label, out.Value)
+	}
+
+	for _, skipped := range s.Skipped {
+		fmt.Fprintf(&b, "Skipped %s: %s\n", skipped.Action, skipped.Reason)
+	}
+
+	fmt.Fprintf(&b, "Action: %s (+%d, score %d)\n", s.Action, s.Points, s.Score)
+
+	return b.String()
+}
+
+// SaveTrace writes the trace to the JSON file at path.
+func SaveTrace(path string, t *Trace) error {
+	data, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding trace: %w", err)
+	}
+
+	return writeFileAtomic(path, data)
+}
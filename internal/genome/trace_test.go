@@ -0,0 +1,138 @@
+package genome
+
+import (
+	"encoding/json"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/iadams749/JumbleBerryFieldsBot/internal/game"
+)
+
+func TestChooseAction(t *testing.T) {
+	t.Parallel()
+
+	// outputs that prefer Mixed Basket, then rerolling while keeping jars 1 and 3, then Free Roll
+	outputs := make([]float64, OutputSize)
+	outputs[1], outputs[3] = 0.5, 0.2
+	outputs[5+game.CatMixed] = 3
+	outputs[14] = 2
+	outputs[5+game.CatFree] = 1
+
+	tests := []struct {
+		name        string
+		setup       func(gs *game.GameState)
+		want        game.Action
+		wantSkipped []game.Action
+	}{
+		{
+			name: "Most preferred action is legal",
+			want: game.NewScore(game.CatMixed),
+		},
+		{
+			name: "Used category is skipped",
+			setup: func(gs *game.GameState) {
+				gs.Categories.MixedCategory.Used = true
+			},
+			want:        game.NewReroll(1, 3),
+			wantSkipped: []game.Action{game.NewScore(game.CatMixed)},
+		},
+		{
+			name: "Reroll without rolls left is skipped",
+			setup: func(gs *game.GameState) {
+				gs.Categories.MixedCategory.Used = true
+				gs.RollsLeftInTurn = 0
+			},
+			want:        game.NewScore(game.CatFree),
+			wantSkipped: []game.Action{game.NewScore(game.CatMixed), game.NewReroll(1, 3)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			gs := game.NewGameWithRoller(nil, game.NewSeededRoller(0))
+			gs.RollJars()
+			if tt.setup != nil {
+				tt.setup(gs)
+			}
+
+			got, skipped, err := ChooseAction(gs, outputs)
+			if err != nil {
+				t.Fatalf("ChooseAction() error = %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("ChooseAction() = %v, want %v", got, tt.want)
+			}
+
+			var gotSkipped []game.Action
+			for _, s := range skipped {
+				if s.Reason == nil {
+					t.Errorf("skipped %v without a reason", s.Action)
+				}
+				gotSkipped = append(gotSkipped, s.Action)
+			}
+
+			if !reflect.DeepEqual(gotSkipped, tt.wantSkipped) {
+				t.Errorf("ChooseAction() skipped %v, want %v", gotSkipped, tt.wantSkipped)
+			}
+		})
+	}
+}
+
+func TestNetwork_TraceMove(t *testing.T) {
+	t.Parallel()
+	n, err := NewNetwork(NewGenome(rand.New(rand.NewSource(3)), []int{8}, nil))
+	if err != nil {
+		t.Fatalf("NewNetwork() error = %v", err)
+	}
+
+	gs := game.NewGameWithRoller(nil, game.NewSeededRoller(5))
+	gs.RollJars()
+
+	trace := &Trace{}
+	for !gs.IsOver() {
+		before := gs.Score
+		step, err := n.TraceMove(gs)
+		if err != nil {
+			t.Fatalf("TraceMove() error = %v", err)
+		}
+
+		if len(step.Outputs) != OutputSize || len(step.Berries) != 5 || step.Action == "" {
+			t.Fatalf("TraceMove() returned an incomplete step %+v", step)
+		}
+
+		if step.Score != gs.Score || step.Points != gs.Score-before {
+			t.Errorf("TraceMove() recorded score %d (+%d), game has %d (+%d)", step.Score, step.Points, gs.Score, gs.Score-before)
+		}
+
+		trace.Steps = append(trace.Steps, step)
+	}
+	trace.FinalScore = gs.Score
+
+	// tracing a game must not change how it is played
+	if want := n.PlayGame(game.NewSeededRoller(5)); trace.FinalScore != want {
+		t.Errorf("traced game scored %d, PlayGame() = %d", trace.FinalScore, want)
+	}
+
+	path := filepath.Join(t.TempDir(), "trace.json")
+	if err := SaveTrace(path, trace); err != nil {
+		t.Fatalf("SaveTrace() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+
+	var loaded Trace
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(&loaded, trace) {
+		t.Errorf("saved trace doesn't round trip")
+	}
+}
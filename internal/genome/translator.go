@@ -9,187 +9,66 @@ import (
 	"gorgonia.org/tensor"
 )
 
-// TranslateGameState takes a game state and converts it to an input tensor for the neural net.
-func TranslateGameState(gs *game.GameState) *tensor.Dense {
-	var inputs []float64
-
-	if len(gs.Jars) != 5 {
-		panic("invalid jar length")
-	}
-
-	// making the berry inputs
-	for _, jar := range gs.Jars {
-		switch jar.Berry {
-		case game.Jumbleberry:
-			inputs = append(inputs, []float64{1.0, 0.0, 0.0, 0.0, 0.0}...)
-		case game.Sugarberry:
-			inputs = append(inputs, []float64{0.0, 1.0, 0.0, 0.0, 0.0}...)
-		case game.Pickleberry:
-			inputs = append(inputs, []float64{0.0, 0.0, 1.0, 0.0, 0.0}...)
-		case game.Moonberry:
-			inputs = append(inputs, []float64{0.0, 0.0, 0.0, 1.0, 0.0}...)
-		case game.Pest:
-			inputs = append(inputs, []float64{0.0, 0.0, 0.0, 0.0, 1.0}...)
-		default:
-			panic("unrecognized berry")
-		}
-	}
-
-	// encoding the round inputs
-	switch gs.RollsLeftInTurn {
-	case 2:
-		inputs = append(inputs, []float64{1.0, 0.0, 0.0}...)
-	case 1:
-		inputs = append(inputs, []float64{0.0, 1.0, 0.0}...)
-	case 0:
-		inputs = append(inputs, []float64{0.0, 0.0, 1.0}...)
-	default:
-		panic("must have rolled at least once")
-	}
-
-	// encoding the categories
-	if gs.Categories.JumbleberryCategory.Used {
-		inputs = append(inputs, 1.0)
-	} else {
-		inputs = append(inputs, 0.0)
-	}
-
-	if gs.Categories.SugarberryCategory.Used {
-		inputs = append(inputs, 1.0)
-	} else {
-		inputs = append(inputs, 0.0)
-	}
-
-	if gs.Categories.PickleberryCategory.Used {
-		inputs = append(inputs, 1.0)
-	} else {
-		inputs = append(inputs, 0.0)
-	}
-
-	if gs.Categories.MoonberryCategory.Used {
-		inputs = append(inputs, 1.0)
-	} else {
-		inputs = append(inputs, 0.0)
-	}
-
-	if gs.Categories.ThreeCategory.Used {
-		inputs = append(inputs, 1.0)
-	} else {
-		inputs = append(inputs, 0.0)
-	}
-
-	if gs.Categories.FourCategory.Used {
-		inputs = append(inputs, 1.0)
-	} else {
-		inputs = append(inputs, 0.0)
-	}
-
-	if gs.Categories.FiveCategory.Used {
-		inputs = append(inputs, 1.0)
-	} else {
-		inputs = append(inputs, 0.0)
-	}
+// berryInputs maps each berry to its position within the five inputs of a jar.
+var berryInputs = map[game.Berry]int{
+	game.Jumbleberry: 0,
+	game.Sugarberry:  1,
+	game.Pickleberry: 2,
+	game.Moonberry:   3,
+	game.Pest:        4,
+}
 
-	if gs.Categories.MixedCategory.Used {
-		inputs = append(inputs, 1.0)
-	} else {
-		inputs = append(inputs, 0.0)
-	}
+// TranslateGameState takes a game state and converts it to an input tensor for the neural net,
+// using the default one-hot encoding.
+func TranslateGameState(gs *game.GameState) *tensor.Dense {
+	return encodeTensor(OneHotEncoder{}, gs)
+}
 
-	if gs.Categories.FreeCategory.Used {
-		inputs = append(inputs, 1.0)
-	} else {
-		inputs = append(inputs, 0.0)
-	}
+// encodeTensor encodes the game state with the encoder into an input tensor for the neural net.
+func encodeTensor(enc Encoder, gs *game.GameState) *tensor.Dense {
+	inputs := make([]float64, enc.Size())
+	enc.Encode(gs, inputs)
 
 	// returning the input as a tensor
 	return tensor.New(
 		tensor.WithBacking(inputs),
-		tensor.WithShape(1, 37), // Shape matches the input layer
+		tensor.WithShape(1, enc.Size()), // Shape matches the input layer
 	)
 }
 
-func DoMoveFromTensor(game *game.GameState, output *gorgonia.Node) error {
-	outputs, topIndices, err := GetTopKValues(output, 15)
-	if err != nil {
-		return fmt.Errorf("DoMoveFromTensor: %w", err)
+// DoMoveFromTensor applies the most preferred legal action from the network output to the game.
+// Outputs 0-4 are the jar locks, 5-13 are the categories in scorecard order and 14 is the re-roll.
+func DoMoveFromTensor(gs *game.GameState, output *gorgonia.Node) error {
+	dense, ok := output.Value().(*tensor.Dense)
+	if !ok {
+		return fmt.Errorf("DoMoveFromTensor: expected a *tensor.Dense, got %T", output.Value())
 	}
 
-outerLoop:
-	for _, val := range topIndices {
-		switch val {
-		case 0, 1, 2, 3, 4:
-			continue
-		case 5:
-			if !game.Categories.JumbleberryCategory.Used {
-				game.ScoreCategory(game.Categories.JumbleberryCategory)
-				break outerLoop
-			}
-		case 6:
-			if !game.Categories.SugarberryCategory.Used {
-				game.ScoreCategory(game.Categories.SugarberryCategory)
-				break outerLoop
-			}
-		case 7:
-			if !game.Categories.PickleberryCategory.Used {
-				game.ScoreCategory(game.Categories.PickleberryCategory)
-				break outerLoop
-			}
-		case 8:
-			if !game.Categories.MoonberryCategory.Used {
-				game.ScoreCategory(game.Categories.MoonberryCategory)
-				break outerLoop
-			}
-		case 9:
-			if !game.Categories.ThreeCategory.Used {
-				game.ScoreCategory(game.Categories.ThreeCategory)
-				break outerLoop
-			}
-		case 10:
-			if !game.Categories.FourCategory.Used {
-				game.ScoreCategory(game.Categories.FourCategory)
-				break outerLoop
-			}
-		case 11:
-			if !game.Categories.FiveCategory.Used {
-				game.ScoreCategory(game.Categories.FiveCategory)
-				break outerLoop
-			}
-		case 12:
-			if !game.Categories.MixedCategory.Used {
-				game.ScoreCategory(game.Categories.MixedCategory)
-				break outerLoop
-			}
-		case 13:
-			if !game.Categories.FreeCategory.Used {
-				game.ScoreCategory(game.Categories.FreeCategory)
-				break outerLoop
-			}
-		case 14:
-			if game.RollsLeftInTurn > 0 {
-				// iterating over the output again to see which jars to lock or unlock
-				for idx, val := range topIndices {
-					if val > 4 {
-						continue
-					} else {
-						// locking or unlocking the jars based on the output
-						if outputs[idx] > 0 {
-							game.Jars[val].Lock()
-						} else {
-							game.Jars[val].Unlock()
-						}
-					}
-				}
-				// rolling the jars
-				game.RollJars()
-
-				break outerLoop
-			}
-
-		}
+	return DoMoveFromOutputs(gs, dense.Data().([]float64))
+}
+
+// DoMoveFromOutputs applies the most preferred legal action from the raw network outputs to the game.
+// The outputs are laid out like the ones DoMoveFromTensor reads.
+func DoMoveFromOutputs(gs *game.GameState, data []float64) error {
+	action, _, err := ChooseAction(gs, data)
+	if err != nil {
+		return fmt.Errorf("DoMoveFromOutputs: %w", err)
 	}
 
-	return nil
+	return gs.Apply(action)
+}
+
+// A SkippedAction is an action the network preferred over the one it took, and why it isn't legal.
+type SkippedAction struct {
+	Action game.Action
+	Reason error
+}
+
+// ChooseAction returns the most preferred legal action from the raw network outputs,
+// along with every action the network preferred over it that isn't legal.
+// It decodes the outputs with an ArgmaxDecoder.
+func ChooseAction(gs *game.GameState, data []float64) (game.Action, []SkippedAction, error) {
+	return ArgmaxDecoder{}.Decode(gs, data)
 }
 
 // GetTopKValues takes a *gorgonia.Node and returns the top K values and their indices
@@ -207,6 +86,12 @@ func GetTopKValues(node *gorgonia.Node, k int) ([]float64, []int, error) {
 	// Flatten the tensor into a 1D slice of floats (if it's not already)
 	data := dense.Data().([]float64)
 
+	topKValues, topKIndices := topK(data, k)
+	return topKValues, topKIndices, nil
+}
+
+// topK returns the k largest values in data and their indices, from largest to smallest.
+func topK(data []float64, k int) ([]float64, []int) {
 	// Create a list of indices and values
 	type pair struct {
 		value float64
@@ -230,5 +115,5 @@ func GetTopKValues(node *gorgonia.Node, k int) ([]float64, []int, error) {
 		topKIndices = append(topKIndices, values[i].index)
 	}
 
-	return topKValues, topKIndices, nil
+	return topKValues, topKIndices
 }
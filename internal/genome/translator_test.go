@@ -140,7 +140,7 @@ func TestTranslateGameState(t *testing.T) {
 						},
 					},
 					RollsLeftInTurn: 2,
-					Categories:      game.NewGame().Categories,
+					Categories:      game.NewGame(game.DefaultRules()).Categories,
 				},
 			},
 			want: tensor.New(
@@ -175,7 +175,7 @@ func TestTranslateGameState(t *testing.T) {
 						},
 					},
 					RollsLeftInTurn: 1,
-					Categories:      game.NewGame().Categories,
+					Categories:      game.NewGame(game.DefaultRules()).Categories,
 				},
 			},
 			want: tensor.New(
@@ -211,7 +211,7 @@ func TestTranslateGameState(t *testing.T) {
 							},
 						},
 						RollsLeftInTurn: 0,
-						Categories:      game.NewGame().Categories,
+						Categories:      game.NewGame(game.DefaultRules()).Categories,
 					}
 
 					state.Categories.JumbleberryCategory.Used = true
@@ -291,7 +291,7 @@ func TestTranslateGameState_Panic(t *testing.T) {
 		{
 			name: "Jars Haven't Been Rolled Yet",
 			args: args{
-				gs: game.NewGame(),
+				gs: game.NewGame(game.DefaultRules()),
 			},
 		},
 	}
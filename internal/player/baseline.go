@@ -0,0 +1,146 @@
+package player
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/iadams749/JumbleBerryFieldsBot/internal/game"
+)
+
+// Random is a Player that picks uniformly from the legal actions.
+type Random struct {
+	rng *rand.Rand
+}
+
+// NewRandom returns a Random player that draws its choices from the provided seed.
+func NewRandom(seed int64) *Random {
+	return &Random{rng: rand.New(rand.NewSource(seed))}
+}
+
+// ChooseAction returns a random legal action.
+func (r *Random) ChooseAction(gs *game.GameState) (game.Action, error) {
+	actions := gs.LegalActions()
+	if len(actions) == 0 {
+		return game.Action{}, fmt.Errorf("no legal action")
+	}
+
+	return actions[r.rng.Intn(len(actions))], nil
+}
+
+// Greedy is a Player that never rerolls, and scores the open category worth the most points right away.
+// Ties go to the category that comes first on the scorecard.
+type Greedy struct{}
+
+// ChooseAction returns the Score action for the open category worth the most points.
+func (Greedy) ChooseAction(gs *game.GameState) (game.Action, error) {
+	best, bestScore := bestCategory(gs)
+	if bestScore < 0 {
+		return game.Action{}, fmt.Errorf("every category has been used")
+	}
+
+	return game.NewScore(best), nil
+}
+
+// bestCategory returns the open category worth the most points with the current berries, and its points.
+// The points are -1 if every category has been used.
+func bestCategory(gs *game.GameState) (game.CategoryID, int) {
+	best, bestScore := game.CategoryID(-1), -1
+	berries := gs.GetBerries()
+
+	for id, cat := range gs.Categories.All() {
+		if cat.IsUsed() {
+			continue
+		}
+
+		if score := cat.PreviewScore(berries); score > bestScore {
+			best, bestScore = game.CategoryID(id), score
+		}
+	}
+
+	return best, bestScore
+}
+
+// RuleBased is a Player with a fixed keep strategy that chases Moonberries and the Mixed Basket:
+//   - while the Mixed Basket is open and at least three kinds of berry have been rolled, it keeps one of each kind and every Moonberry;
+//   - otherwise it keeps every Moonberry, or the most common berry if there are none, hoping for a set;
+//   - it scores the best category once it has no rolls left or the Mixed Basket is complete, and when nothing
+//     scores it gives up the hardest category that is still open.
+type RuleBased struct{}
+
+// ChooseAction returns the action the rules pick.
+func (RuleBased) ChooseAction(gs *game.GameState) (game.Action, error) {
+	if gs.IsOver() {
+		return game.Action{}, fmt.Errorf("every category has been used")
+	}
+
+	berries := gs.GetBerries()
+	mixedOpen := !gs.Categories.MixedCategory.IsUsed()
+
+	if gs.RollsLeftInTurn > 0 && !(mixedOpen && gs.Categories.MixedCategory.PreviewScore(berries) > 0) {
+		if keep := ruleKeep(berries, mixedOpen); keep != 1<<len(berries)-1 {
+			return game.Action{Kind: game.Reroll, Keep: keep}, nil
+		}
+	}
+
+	best, bestScore := bestCategory(gs)
+	if bestScore > 0 {
+		return game.NewScore(best), nil
+	}
+
+	// nothing scores, so give up the category that is the least likely to score later
+	for _, id := range []game.CategoryID{game.CatFive, game.CatFour, game.CatMixed, game.CatThree} {
+		if !gs.Categories.Get(id).IsUsed() {
+			return game.NewScore(id), nil
+		}
+	}
+
+	return game.NewScore(best), nil
+}
+
+// ruleKeep returns the keep mask the rules of RuleBased pick for the berries.
+func ruleKeep(berries []game.Berry, mixedOpen bool) uint {
+	counts := make(map[game.Berry]int)
+	for _, b := range berries {
+		counts[b]++
+	}
+
+	kinds := 0
+	for _, b := range []game.Berry{game.Jumbleberry, game.Sugarberry, game.Pickleberry, game.Moonberry} {
+		if counts[b] > 0 {
+			kinds++
+		}
+	}
+
+	var keep uint
+	if mixedOpen && kinds >= 3 {
+		seen := make(map[game.Berry]bool)
+		for i, b := range berries {
+			if b != game.Pest && (!seen[b] || b == game.Moonberry) {
+				keep |= 1 << i
+				seen[b] = true
+			}
+		}
+
+		return keep
+	}
+
+	target := game.Moonberry
+	if counts[game.Moonberry] == 0 {
+		// the most common berry, preferring the more valuable one, which comes later in the berry order
+		most := 0
+		target = game.Pest
+		for _, b := range []game.Berry{game.Jumbleberry, game.Sugarberry, game.Pickleberry} {
+			if counts[b] > 0 && counts[b] >= most {
+				target, most = b, counts[b]
+			}
+		}
+	}
+
+	for i, b := range berries {
+		if b == target && b != game.Pest {
+			keep |= 1 << i
+		}
+	}
+
+	return keep
+}
@@ -0,0 +1,96 @@
+// Package player defines the Player interface every kind of player implements, and the baseline bots
+// the trained networks are measured against.
+package player
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/iadams749/JumbleBerryFieldsBot/internal/game"
+)
+
+// ErrStalled is returned when a game isn't over after the most actions it could take,
+// which means the player keeps choosing actions that don't make progress.
+var ErrStalled = errors.New("game stalled")
+
+// A Player chooses the moves in a game.
+type Player interface {
+	// ChooseAction returns the action to take in the game state, without applying it.
+	// It returns an error if the player can't choose a legal action.
+	ChooseAction(gs *game.GameState) (game.Action, error)
+}
+
+// Func adapts a function to the Player interface, like the BestAction method of a solver.
+type Func func(gs *game.GameState) (game.Action, error)
+
+// ChooseAction calls f.
+func (f Func) ChooseAction(gs *game.GameState) (game.Action, error) {
+	return f(gs)
+}
+
+// Play plays a full game with the default rules, drawing every roll from r, and returns the final score.
+// If r is nil, a randomly seeded Roller is used.
+func Play(p Player, r game.Roller) (int, error) {
+	gs := game.NewGameWithRoller(game.DefaultRules(), r)
+	gs.RollJars()
+
+	err := PlayGame(p, gs)
+	return gs.Score, err
+}
+
+// PlayGame plays the game to the end, so its final scorecard can be inspected.
+// It gives up with ErrStalled if the game isn't over after the most actions it could take.
+func PlayGame(p Player, gs *game.GameState) error {
+	return PlayGameWithBudget(p, gs, gs.MaxActionsLeft())
+}
+
+// PlayGameWithBudget plays the game like PlayGame, but gives up with ErrStalled once the player has taken steps actions
+// without finishing it.
+func PlayGameWithBudget(p Player, gs *game.GameState, steps int) error {
+	for taken := 0; !gs.IsOver(); taken++ {
+		if taken >= steps {
+			return fmt.Errorf("%w: not over after %d actions", ErrStalled, taken)
+		}
+
+		action, err := p.ChooseAction(gs)
+		if err != nil {
+			return err
+		}
+
+		if err := gs.Apply(action); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// PlayMatch plays the match to the end, with players[i] choosing the moves of the i-th player in the match.
+// Like PlayGame, it gives up with ErrStalled if the match isn't over after the most actions it could take.
+func PlayMatch(m *game.Match, players []Player) error {
+	if len(players) != len(m.Players) {
+		return fmt.Errorf("match has %d players, but %d were provided", len(m.Players), len(players))
+	}
+
+	steps := 0
+	for _, mp := range m.Players {
+		steps += mp.State().MaxActionsLeft()
+	}
+
+	for taken := 0; !m.IsOver(); taken++ {
+		if taken >= steps {
+			return fmt.Errorf("%w: match not over after %d actions", ErrStalled, taken)
+		}
+
+		action, err := players[m.Active()].ChooseAction(m.Current().State())
+		if err != nil {
+			return fmt.Errorf("%s: %w", m.Current().Name, err)
+		}
+
+		if err := m.Apply(m.Active(), action); err != nil {
+			return fmt.Errorf("%s: %w", m.Current().Name, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,193 @@
+package player
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/iadams749/JumbleBerryFieldsBot/internal/game"
+)
+
+// position returns a game at the provided position, failing the test if it's invalid.
+func position(t *testing.T, dice string, rollsLeft int, used ...game.CategoryID) *game.GameState {
+	t.Helper()
+	berries, err := game.ParseBerries(dice)
+	if err != nil {
+		t.Fatalf("ParseBerries() error = %v", err)
+	}
+
+	usedCats := make(map[game.CategoryID]int)
+	for _, id := range used {
+		usedCats[id] = 0
+	}
+
+	gs, err := game.NewGameFromPosition(nil, game.Position{Berries: berries, RollsLeft: rollsLeft, Used: usedCats})
+	if err != nil {
+		t.Fatalf("NewGameFromPosition() error = %v", err)
+	}
+
+	return gs
+}
+
+func TestGreedy(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		dice string
+		used []game.CategoryID
+		want game.Action
+	}{
+		{name: "Most points", dice: "M M M J J", used: []game.CategoryID{game.CatThree}, want: game.NewScore(game.CatFree)},
+		{name: "Five of a kind used", dice: "M M M M M", used: []game.CategoryID{game.CatFive}, want: game.NewScore(game.CatMoonberry)},
+		{name: "Ties go to the first category", dice: "X X X X X", used: []game.CategoryID{game.CatThree, game.CatFour, game.CatFive, game.CatFree}, want: game.NewScore(game.CatJumbleberry)},
+		{name: "Never rerolls", dice: "X X X X J", want: game.NewScore(game.CatJumbleberry)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := Greedy{}.ChooseAction(position(t, tt.dice, 2, tt.used...))
+			if err != nil {
+				t.Fatalf("ChooseAction() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ChooseAction() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRuleBased(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		dice      string
+		rollsLeft int
+		used      []game.CategoryID
+		want      game.Action
+	}{
+		{name: "Chases the Mixed Basket", dice: "J J S M X", rollsLeft: 2, want: game.NewReroll(0, 2, 3)},
+		{name: "Keeps extra Moonberries for the Mixed Basket", dice: "M S M P X", rollsLeft: 1, want: game.NewReroll(0, 1, 2, 3)},
+		{name: "Scores a complete Mixed Basket", dice: "J S P M M", rollsLeft: 2, want: game.NewScore(game.CatMixed)},
+		{name: "Keeps Moonberries", dice: "J M X S M", rollsLeft: 2, used: []game.CategoryID{game.CatMixed}, want: game.NewReroll(1, 4)},
+		{name: "Keeps the most common berry without Moonberries", dice: "J P X P J", rollsLeft: 2, used: []game.CategoryID{game.CatMixed}, want: game.NewReroll(1, 3)},
+		{name: "Rerolls pests", dice: "X X X X X", rollsLeft: 1, want: game.NewReroll()},
+		{name: "Scores with no rolls left", dice: "M M M J J", rollsLeft: 0, want: game.NewScore(game.CatThree)},
+		{name: "Gives up the hardest category", dice: "X X P X X", rollsLeft: 0, used: []game.CategoryID{game.CatPickleberry, game.CatThree, game.CatFour, game.CatFree}, want: game.NewScore(game.CatFive)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := RuleBased{}.ChooseAction(position(t, tt.dice, tt.rollsLeft, tt.used...))
+			if err != nil {
+				t.Fatalf("ChooseAction() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ChooseAction() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRandom(t *testing.T) {
+	t.Parallel()
+	gs := position(t, "J S P M X", 2)
+	legal := make(map[game.Action]bool)
+	for _, a := range gs.LegalActions() {
+		legal[a] = true
+	}
+
+	seen := make(map[game.Action]bool)
+	r := NewRandom(1)
+	for range 1000 {
+		a, err := r.ChooseAction(gs)
+		if err != nil {
+			t.Fatalf("ChooseAction() error = %v", err)
+		}
+		if !legal[a] {
+			t.Fatalf("ChooseAction() = %v, which isn't legal", a)
+		}
+		seen[a] = true
+	}
+
+	if len(seen) < len(legal)/2 {
+		t.Errorf("ChooseAction() only picked %d of %d legal actions", len(seen), len(legal))
+	}
+}
+
+// TestPlay checks that every baseline finishes its games, and that the smarter baselines score more.
+func TestPlay(t *testing.T) {
+	t.Parallel()
+	players := []struct {
+		name   string
+		player Player
+	}{
+		{name: "random", player: NewRandom(1)},
+		{name: "greedy", player: Greedy{}},
+		{name: "rules", player: RuleBased{}},
+	}
+
+	means := make([]float64, len(players))
+	for i, p := range players {
+		total := 0
+		for seed := range int64(200) {
+			score, err := Play(p.player, game.NewSeededRoller(seed))
+			if err != nil {
+				t.Fatalf("Play() with %s error = %v", p.name, err)
+			}
+			total += score
+		}
+		means[i] = float64(total) / 200
+	}
+
+	for i := 1; i < len(players); i++ {
+		if means[i] <= means[i-1] {
+			t.Errorf("%s averaged %.1f, want more than %s with %.1f", players[i].name, means[i], players[i-1].name, means[i-1])
+		}
+	}
+}
+
+func TestPlayGameWithBudget(t *testing.T) {
+	t.Parallel()
+	// the random player rerolls a lot, so it needs most of the budget
+	gs := game.NewGameWithRoller(nil, game.NewSeededRoller(3))
+	gs.RollJars()
+	if err := PlayGameWithBudget(NewRandom(3), gs, gs.MaxActionsLeft()); err != nil {
+		t.Fatalf("PlayGameWithBudget() error = %v", err)
+	}
+
+	gs = game.NewGameWithRoller(nil, game.NewSeededRoller(3))
+	gs.RollJars()
+	err := PlayGameWithBudget(Greedy{}, gs, 5)
+	if !errors.Is(err, ErrStalled) {
+		t.Fatalf("PlayGameWithBudget() error = %v, want %v", err, ErrStalled)
+	}
+
+	if gs.RoundsCompleted != 5 {
+		t.Errorf("PlayGameWithBudget() played %d rounds, want 5", gs.RoundsCompleted)
+	}
+}
+
+func TestPlayMatch(t *testing.T) {
+	t.Parallel()
+	m, err := game.NewMatch(nil, []string{"greedy", "rules"}, game.NewSeededRoller(2))
+	if err != nil {
+		t.Fatalf("NewMatch() error = %v", err)
+	}
+
+	if err := PlayMatch(m, []Player{Greedy{}}); err == nil {
+		t.Errorf("PlayMatch() expected an error for a missing player")
+	}
+
+	if err := PlayMatch(m, []Player{Greedy{}, RuleBased{}}); err != nil {
+		t.Fatalf("PlayMatch() error = %v", err)
+	}
+
+	if !m.IsOver() {
+		t.Errorf("match isn't over after PlayMatch()")
+	}
+
+	illegal := Func(func(*game.GameState) (game.Action, error) { return game.NewScore(game.CatFree), nil })
+	m, _ = game.NewMatch(nil, []string{"a"}, game.NewSeededRoller(2))
+	if err := PlayMatch(m, []Player{illegal}); err == nil {
+		t.Errorf("PlayMatch() expected an error for a player that repeats a category")
+	}
+}
@@ -0,0 +1,201 @@
+// Package server hosts games of Jumbleberry Fields over HTTP with a JSON API.
+//
+// The API has four endpoints:
+//
+//	POST /games                    create a game, optionally seeded with {"seed": 42}
+//	GET  /games/{id}               get the state of a game
+//	POST /games/{id}/actions       apply an action, like {"kind":"reroll","keep":[0,3]} or {"kind":"score","category":"mixedBasket"}
+//	GET  /games/{id}/suggestion    ask the loaded genome for its move
+//
+// Every endpoint that returns a game responds with its id, state, whether it is over and its legal actions.
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/iadams749/JumbleBerryFieldsBot/internal/advisor"
+	"github.com/iadams749/JumbleBerryFieldsBot/internal/game"
+	"github.com/iadams749/JumbleBerryFieldsBot/internal/player"
+)
+
+// A Server handles the HTTP API for the games in its Store.
+type Server struct {
+	store *Store
+	mux   *http.ServeMux
+
+	// suggest chooses the move returned by the suggestion endpoint. It may be nil.
+	// It is called with suggestMu held, since players like a genome network aren't safe for concurrent use.
+	suggest   player.Player
+	suggestMu sync.Mutex
+}
+
+// New returns a Server for the games in store. If suggest is nil, the suggestion endpoint isn't available.
+func New(store *Store, suggest player.Player) *Server {
+	s := &Server{store: store, mux: http.NewServeMux(), suggest: suggest}
+
+	s.mux.HandleFunc("POST /games", s.createGame)
+	s.mux.HandleFunc("GET /games/{id}", s.getGame)
+	s.mux.HandleFunc("POST /games/{id}/actions", s.applyAction)
+	s.mux.HandleFunc("GET /games/{id}/suggestion", s.suggestion)
+
+	return s
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// gameResponse is the body returned by every endpoint that returns a game.
+type gameResponse struct {
+	ID           string          `json:"id"`
+	State        *game.GameState `json:"state"`
+	Over         bool            `json:"over"`
+	LegalActions []game.Action   `json:"legalActions"`
+}
+
+// suggestionResponse is the body returned by the suggestion endpoint.
+type suggestionResponse struct {
+	Action      game.Action `json:"action"`
+	Description string      `json:"description"`
+}
+
+// createRequest is the optional body of a request to create a game.
+type createRequest struct {
+	// Seed seeds the dice of the game. A seed of 0 picks a random seed.
+	Seed int64 `json:"seed"`
+}
+
+// createGame starts a new game and rolls its jars.
+func (s *Server) createGame(w http.ResponseWriter, r *http.Request) {
+	var req createRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return
+	}
+
+	var roller game.Roller
+	if req.Seed != 0 {
+		roller = game.NewSeededRoller(req.Seed)
+	}
+
+	gs := game.NewGameWithRoller(game.DefaultRules(), roller)
+	gs.RollJars()
+
+	id := s.store.add(gs)
+	writeJSON(w, http.StatusCreated, newGameResponse(id, gs))
+}
+
+// getGame returns the state of a game.
+func (s *Server) getGame(w http.ResponseWriter, r *http.Request) {
+	id, sess := s.session(w, r)
+	if sess == nil {
+		return
+	}
+
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, newGameResponse(id, sess.gs))
+}
+
+// applyAction applies the action in the request body to a game.
+func (s *Server) applyAction(w http.ResponseWriter, r *http.Request) {
+	id, sess := s.session(w, r)
+	if sess == nil {
+		return
+	}
+
+	var action game.Action
+	if err := json.NewDecoder(r.Body).Decode(&action); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid action: %w", err))
+		return
+	}
+
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+
+	if err := sess.gs.Apply(action); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newGameResponse(id, sess.gs))
+}
+
+// suggestion returns the move the loaded player would make in a game, without applying it.
+func (s *Server) suggestion(w http.ResponseWriter, r *http.Request) {
+	if s.suggest == nil {
+		writeError(w, http.StatusNotImplemented, fmt.Errorf("no genome is loaded"))
+		return
+	}
+
+	_, sess := s.session(w, r)
+	if sess == nil {
+		return
+	}
+
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+
+	if sess.gs.IsOver() {
+		writeError(w, http.StatusConflict, fmt.Errorf("game is over"))
+		return
+	}
+
+	s.suggestMu.Lock()
+	action, err := s.suggest.ChooseAction(sess.gs)
+	s.suggestMu.Unlock()
+
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, suggestionResponse{Action: action, Description: advisor.Describe(sess.gs, action)})
+}
+
+// session returns the game named in the request path, writing a not found error if it doesn't exist.
+func (s *Server) session(w http.ResponseWriter, r *http.Request) (string, *session) {
+	id := r.PathValue("id")
+
+	sess := s.store.get(id)
+	if sess == nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("game %q not found", id))
+	}
+
+	return id, sess
+}
+
+// newGameResponse describes a game. The session of the game must be locked.
+func newGameResponse(id string, gs *game.GameState) gameResponse {
+	resp := gameResponse{ID: id, State: gs, Over: gs.IsOver(), LegalActions: []game.Action{}}
+	if !resp.Over {
+		resp.LegalActions = gs.LegalActions()
+	}
+
+	return resp
+}
+
+// writeJSON writes v as the JSON body of the response.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(data)
+}
+
+// writeError writes an error as a JSON body like {"error": "game is over"}.
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,147 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iadams749/JumbleBerryFieldsBot/internal/game"
+	"github.com/iadams749/JumbleBerryFieldsBot/internal/player"
+)
+
+// testResponse is the decoded body of a response.
+type testResponse struct {
+	ID           string          `json:"id"`
+	State        *game.GameState `json:"state"`
+	Over         bool            `json:"over"`
+	LegalActions []game.Action   `json:"legalActions"`
+	Action       game.Action     `json:"action"`
+	Description  string          `json:"description"`
+	Error        string          `json:"error"`
+}
+
+// do sends a request to the server and decodes the response, failing the test if the status isn't wantStatus.
+func do(t *testing.T, srv http.Handler, method, path, body string, wantStatus int) testResponse {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+
+	if rec.Code != wantStatus {
+		t.Fatalf("%s %s = %d %s, want %d", method, path, rec.Code, rec.Body, wantStatus)
+	}
+
+	var resp testResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("%s %s returned invalid JSON %s: %v", method, path, rec.Body, err)
+	}
+
+	return resp
+}
+
+func TestServer_PlayGame(t *testing.T) {
+	t.Parallel()
+	srv := New(NewStore(time.Hour), player.Greedy{})
+
+	created := do(t, srv, "POST", "/games", `{"seed":7}`, http.StatusCreated)
+	if created.ID == "" || created.State == nil || created.Over {
+		t.Fatalf("POST /games = %+v, want a new game", created)
+	}
+
+	if created.State.RollsLeftInTurn != 2 || len(created.LegalActions) != 32+game.NumCategories {
+		t.Errorf("new game has %d rolls left and %d legal actions, want the jars rolled once", created.State.RollsLeftInTurn, len(created.LegalActions))
+	}
+
+	// the same seed deals the same berries
+	again := do(t, srv, "POST", "/games", `{"seed":7}`, http.StatusCreated)
+	if again.ID == created.ID || !equalBerries(again.State, created.State) {
+		t.Errorf("games with the same seed have different berries")
+	}
+
+	path := "/games/" + created.ID
+	after := do(t, srv, "POST", path+"/actions", `{"kind":"reroll","keep":[0,1]}`, http.StatusOK)
+	if after.State.RollsLeftInTurn != 1 || !after.State.Jars[0].Locked {
+		t.Errorf("reroll wasn't applied, state = %+v", after.State)
+	}
+
+	got := do(t, srv, "GET", path, "", http.StatusOK)
+	if !equalBerries(got.State, after.State) {
+		t.Errorf("GET %s doesn't return the state after the action", path)
+	}
+
+	for !got.Over {
+		suggestion := do(t, srv, "GET", path+"/suggestion", "", http.StatusOK)
+		if suggestion.Description == "" {
+			t.Errorf("suggestion has no description")
+		}
+
+		action, err := json.Marshal(suggestion.Action)
+		if err != nil {
+			t.Fatalf("Marshal() error = %v", err)
+		}
+		got = do(t, srv, "POST", path+"/actions", string(action), http.StatusOK)
+	}
+
+	if got.State.RoundsCompleted != game.NumCategories || len(got.LegalActions) != 0 {
+		t.Errorf("finished game = %+v, want every category scored and no legal actions", got)
+	}
+
+	do(t, srv, "GET", path+"/suggestion", "", http.StatusConflict)
+	do(t, srv, "POST", path+"/actions", `{"kind":"score","category":"freeRoll"}`, http.StatusUnprocessableEntity)
+}
+
+func TestServer_Errors(t *testing.T) {
+	t.Parallel()
+	srv := New(NewStore(time.Hour), nil)
+	id := do(t, srv, "POST", "/games", "", http.StatusCreated).ID
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		want   int
+	}{
+		{name: "Unknown game", method: "GET", path: "/games/abc", want: http.StatusNotFound},
+		{name: "Action on unknown game", method: "POST", path: "/games/abc/actions", body: `{"kind":"reroll"}`, want: http.StatusNotFound},
+		{name: "Invalid seed", method: "POST", path: "/games", body: `{"seed":"x"}`, want: http.StatusBadRequest},
+		{name: "Invalid action", method: "POST", path: "/games/" + id + "/actions", body: `{"kind":"pass"}`, want: http.StatusBadRequest},
+		{name: "Illegal action", method: "POST", path: "/games/" + id + "/actions", body: `{"kind":"reroll","keep":[7]}`, want: http.StatusUnprocessableEntity},
+		{name: "No genome loaded", method: "GET", path: "/games/" + id + "/suggestion", want: http.StatusNotImplemented},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			resp := do(t, srv, tt.method, tt.path, tt.body, tt.want)
+			if resp.Error == "" {
+				t.Errorf("error response has no message")
+			}
+		})
+	}
+}
+
+func TestServer_ExpiredGame(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	store := NewStore(time.Minute)
+	store.now = func() time.Time { return now }
+	srv := New(store, nil)
+
+	id := do(t, srv, "POST", "/games", "", http.StatusCreated).ID
+	now = now.Add(2 * time.Minute)
+	do(t, srv, "GET", "/games/"+id, "", http.StatusNotFound)
+}
+
+// equalBerries returns true if the jars of both games hold the same berries.
+func equalBerries(a, b *game.GameState) bool {
+	for i := range a.Jars {
+		if a.Jars[i].Berry != b.Jars[i].Berry {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,108 @@
+package server
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/iadams749/JumbleBerryFieldsBot/internal/game"
+)
+
+// A session is one game hosted by the server.
+type session struct {
+	mu       sync.Mutex
+	gs       *game.GameState
+	lastUsed time.Time
+}
+
+// A Store holds the games being played, and forgets a game once it hasn't been used for a while.
+type Store struct {
+	mu       sync.Mutex
+	sessions map[string]*session
+	ttl      time.Duration
+
+	// now returns the current time, it is replaced in tests.
+	now func() time.Time
+}
+
+// NewStore returns an empty Store that expires games that haven't been used for ttl.
+func NewStore(ttl time.Duration) *Store {
+	return &Store{
+		sessions: make(map[string]*session),
+		ttl:      ttl,
+		now:      time.Now,
+	}
+}
+
+// add stores a game and returns its new id. Expired games are removed at the same time.
+func (s *Store) add(gs *game.GameState) string {
+	id := newID()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep()
+	s.sessions[id] = &session{gs: gs, lastUsed: s.now()}
+
+	return id
+}
+
+// get returns the session with the provided id, or nil if it doesn't exist or has expired.
+func (s *Store) get(id string) *session {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sess, ok := s.sessions[id]
+	if !ok {
+		return nil
+	}
+
+	if s.expired(sess) {
+		delete(s.sessions, id)
+		return nil
+	}
+
+	sess.lastUsed = s.now()
+	return sess
+}
+
+// Len returns the number of games in the store, including expired ones that haven't been removed yet.
+func (s *Store) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.sessions)
+}
+
+// Sweep removes every expired game.
+func (s *Store) Sweep() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep()
+}
+
+// sweep removes every expired game. The store must be locked.
+func (s *Store) sweep() {
+	for id, sess := range s.sessions {
+		if s.expired(sess) {
+			delete(s.sessions, id)
+		}
+	}
+}
+
+// expired returns true if the session hasn't been used for longer than the ttl. The store must be locked.
+func (s *Store) expired(sess *session) bool {
+	return s.now().Sub(sess.lastUsed) > s.ttl
+}
+
+// newID returns a random id for a game.
+func newID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err.Error())
+	}
+
+	return hex.EncodeToString(b)
+}
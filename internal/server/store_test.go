@@ -0,0 +1,62 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/iadams749/JumbleBerryFieldsBot/internal/game"
+)
+
+func TestStore_Expiry(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	store := NewStore(time.Minute)
+	store.now = func() time.Time { return now }
+
+	kept := store.add(game.NewGame(nil))
+	dropped := store.add(game.NewGame(nil))
+
+	now = now.Add(50 * time.Second)
+	if store.get(kept) == nil {
+		t.Fatalf("get() = nil before the game expired")
+	}
+
+	// using a game resets its expiry
+	now = now.Add(50 * time.Second)
+	if store.get(kept) == nil {
+		t.Errorf("get() = nil for a game that was just used")
+	}
+
+	if store.get(dropped) != nil {
+		t.Errorf("get() returned an expired game")
+	}
+
+	if store.get("unknown") != nil {
+		t.Errorf("get() returned a game for an unknown id")
+	}
+
+	now = now.Add(2 * time.Minute)
+	store.Sweep()
+	if store.Len() != 0 {
+		t.Errorf("Len() = %d after sweeping expired games, want 0", store.Len())
+	}
+}
+
+func TestStore_AddSweeps(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	store := NewStore(time.Minute)
+	store.now = func() time.Time { return now }
+
+	first := store.add(game.NewGame(nil))
+	now = now.Add(2 * time.Minute)
+	second := store.add(game.NewGame(nil))
+
+	if first == second {
+		t.Errorf("add() returned the same id twice")
+	}
+
+	if store.Len() != 1 {
+		t.Errorf("Len() = %d, want 1 since adding a game removes expired ones", store.Len())
+	}
+}
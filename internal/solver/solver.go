@@ -0,0 +1,399 @@
+// Package solver computes the expected-value-optimal strategy for solitaire Jumbleberry Fields.
+//
+// Because categories score independently of each other, the expected score of the rest of a game only depends on
+// which categories have been used. The solver works backwards from the full scorecard, and for every set of used
+// categories it computes the value of every dice multiset at every number of rolls left in the turn.
+package solver
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/iadams749/JumbleBerryFieldsBot/internal/game"
+)
+
+// numFaces is the number of faces on a die.
+const numFaces = 5
+
+// allUsed is the used-category bitmask of a finished game.
+const allUsed = 1<<game.NumCategories - 1
+
+// A hand is a multiset of dice, stored as the count of each face indexed by game.Berry.
+type hand [numFaces]int
+
+// size returns the number of dice in the hand.
+func (h hand) size() int {
+	n := 0
+	for _, count := range h {
+		n += count
+	}
+
+	return n
+}
+
+// add returns the union of two hands.
+func (h hand) add(other hand) hand {
+	for i := range h {
+		h[i] += other[i]
+	}
+
+	return h
+}
+
+// berries returns the hand as a slice of berries.
+func (h hand) berries() []game.Berry {
+	var berries []game.Berry
+	for face, count := range h {
+		for range count {
+			berries = append(berries, game.Berry(face))
+		}
+	}
+
+	return berries
+}
+
+// handOf returns the hand made up of the provided berries.
+func handOf(berries []game.Berry) hand {
+	var h hand
+	for _, berry := range berries {
+		h[berry]++
+	}
+
+	return h
+}
+
+// A turnTable holds the values of every position within a turn for one set of used categories.
+type turnTable struct {
+	// value[r][i] is the expected future score of holding full hand i with r rolls left, when playing optimally.
+	value [][]float64
+
+	// keep[r][i] is the expected future score of keeping hand i and rolling the other dice,
+	// leaving r rolls left after the roll.
+	keep [][]float64
+}
+
+// An ActionValue is an action together with the final score it is expected to lead to.
+type ActionValue struct {
+	Action game.Action
+	Value  float64
+}
+
+// A Solver holds the optimal strategy for a RuleSet.
+type Solver struct {
+	rules      *game.RuleSet
+	categories []game.Category
+
+	// hands holds every hand of 0 up to JarCount dice, and index maps a hand back to its position.
+	hands []hand
+	index map[hand]int
+
+	// rolls[m] holds the index and probability of every outcome of rolling m dice.
+	rolls [][]outcome
+
+	// full holds the indices of the hands with JarCount dice, and subHands[i] the indices of every hand
+	// that can be kept from full hand i.
+	full     []int
+	subHands [][]int
+
+	// next[k][j] is the position in full of keeping hand k and rolling outcome j of the remaining dice.
+	next [][]int
+
+	// scores[i][c] is the score of full hand i in category c.
+	scores [][]int
+
+	// future[mask] is the expected score of the rest of the game at the start of a turn,
+	// with the categories in mask already used.
+	future []float64
+	turns  []*turnTable
+}
+
+// outcome is one possible result of rolling some dice.
+type outcome struct {
+	hand int
+	prob float64
+}
+
+// New computes the optimal strategy for the provided rules.
+// If rules is nil, DefaultRules are used.
+func New(rules *game.RuleSet) *Solver {
+	if rules == nil {
+		rules = game.DefaultRules()
+	}
+
+	s := &Solver{
+		rules:      rules,
+		categories: game.NewGame(rules).Categories.All(),
+		index:      make(map[hand]int),
+		future:     make([]float64, allUsed+1),
+		turns:      make([]*turnTable, allUsed+1),
+	}
+
+	s.buildHands()
+
+	for mask := allUsed - 1; mask >= 0; mask-- {
+		s.solveTurn(mask)
+	}
+
+	return s
+}
+
+// buildHands enumerates every hand and the probability of rolling it.
+func (s *Solver) buildHands() {
+	n := s.rules.JarCount
+
+	total := 0.0
+	for face := range numFaces {
+		total += s.rules.FaceWeights.Of(game.Berry(face))
+	}
+
+	var probs [numFaces]float64
+	for face := range numFaces {
+		probs[face] = s.rules.FaceWeights.Of(game.Berry(face)) / total
+	}
+
+	s.rolls = make([][]outcome, n+1)
+	for m := 0; m <= n; m++ {
+		for _, h := range handsOfSize(m) {
+			idx := len(s.hands)
+			s.hands = append(s.hands, h)
+			s.index[h] = idx
+			s.rolls[m] = append(s.rolls[m], outcome{hand: idx, prob: multinomial(h, probs)})
+		}
+	}
+
+	for _, o := range s.rolls[n] {
+		h := s.hands[o.hand]
+		s.full = append(s.full, o.hand)
+
+		var subs []int
+		for _, sub := range subHandsOf(h) {
+			subs = append(subs, s.index[sub])
+		}
+		s.subHands = append(s.subHands, subs)
+
+		berries := h.berries()
+		scores := make([]int, game.NumCategories)
+		for c, cat := range s.categories {
+			scores[c] = cat.PreviewScore(berries)
+		}
+		s.scores = append(s.scores, scores)
+	}
+
+	s.next = make([][]int, len(s.hands))
+	for k, kept := range s.hands {
+		for _, o := range s.rolls[n-kept.size()] {
+			s.next[k] = append(s.next[k], s.fullPos(kept.add(s.hands[o.hand])))
+		}
+	}
+}
+
+// fullPos returns the position of a hand with JarCount dice in full.
+func (s *Solver) fullPos(h hand) int {
+	return s.index[h] - s.full[0]
+}
+
+// solveTurn computes the turn table and expected future score for one set of used categories.
+// Every set with more categories used must already be solved.
+func (s *Solver) solveTurn(mask int) {
+	rollsLeft := s.rules.RollsPerTurn - 1
+	n := s.rules.JarCount
+
+	t := &turnTable{
+		value: make([][]float64, rollsLeft+1),
+		keep:  make([][]float64, rollsLeft),
+	}
+
+	for r := 0; r <= rollsLeft; r++ {
+		if r > 0 {
+			// the value of keeping each hand and rolling the rest, leaving r-1 rolls
+			t.keep[r-1] = make([]float64, len(s.hands))
+			for k, kept := range s.hands {
+				expected := 0.0
+				for j, o := range s.rolls[n-kept.size()] {
+					expected += o.prob * t.value[r-1][s.next[k][j]]
+				}
+				t.keep[r-1][k] = expected
+			}
+		}
+
+		t.value[r] = make([]float64, len(s.full))
+		for i := range s.full {
+			best := s.bestScore(mask, i)
+			if r > 0 {
+				for _, k := range s.subHands[i] {
+					best = max(best, t.keep[r-1][k])
+				}
+			}
+			t.value[r][i] = best
+		}
+	}
+
+	expected := 0.0
+	for i, o := range s.rolls[n] {
+		expected += o.prob * t.value[rollsLeft][i]
+	}
+
+	s.future[mask] = expected
+	s.turns[mask] = t
+}
+
+// bestScore returns the best value of scoring full hand i now in one of the open categories.
+func (s *Solver) bestScore(mask, i int) float64 {
+	best := math.Inf(-1)
+	for c := range game.NumCategories {
+		if mask&(1<<c) == 0 {
+			best = max(best, float64(s.scores[i][c])+s.future[mask|1<<c])
+		}
+	}
+
+	return best
+}
+
+// ExpectedScore returns the expected final score of a game played optimally from the start.
+func (s *Solver) ExpectedScore() float64 {
+	return s.future[0]
+}
+
+// Evaluate returns every distinct legal action in the game state with the final score it is expected to lead to
+// when playing optimally afterwards, sorted from best to worst.
+// Rerolls that keep the same berries are only listed once, with the lowest keep mask.
+func (s *Solver) Evaluate(gs *game.GameState) ([]ActionValue, error) {
+	if gs.IsOver() {
+		return nil, fmt.Errorf("game is over")
+	}
+
+	if len(gs.Jars) != s.rules.JarCount {
+		return nil, fmt.Errorf("solver expects %d jars, got %d", s.rules.JarCount, len(gs.Jars))
+	}
+
+	mask := usedMask(gs)
+	base := float64(gs.Score)
+
+	if gs.RollsLeftInTurn >= s.rules.RollsPerTurn {
+		return []ActionValue{{Action: game.NewReroll(), Value: base + s.future[mask]}}, nil
+	}
+
+	berries := gs.GetBerries()
+	for _, berry := range berries {
+		if berry < 0 || berry >= numFaces {
+			return nil, fmt.Errorf("invalid berry %d", berry)
+		}
+	}
+
+	i := s.fullPos(handOf(berries))
+	t := s.turns[mask]
+
+	var values []ActionValue
+	for c := range game.NumCategories {
+		if mask&(1<<c) == 0 {
+			values = append(values, ActionValue{
+				Action: game.NewScore(game.CategoryID(c)),
+				Value:  base + float64(s.scores[i][c]) + s.future[mask|1<<c],
+			})
+		}
+	}
+
+	if r := gs.RollsLeftInTurn; r > 0 {
+		seen := make(map[hand]bool)
+		for keep := range uint(1) << len(berries) {
+			var kept hand
+			for jar, berry := range berries {
+				if keep&(1<<jar) != 0 {
+					kept[berry]++
+				}
+			}
+
+			if seen[kept] {
+				continue
+			}
+			seen[kept] = true
+
+			values = append(values, ActionValue{
+				Action: game.Action{Kind: game.Reroll, Keep: keep},
+				Value:  base + t.keep[r-1][s.index[kept]],
+			})
+		}
+	}
+
+	sort.SliceStable(values, func(a, b int) bool {
+		return values[a].Value > values[b].Value
+	})
+
+	return values, nil
+}
+
+// BestAction returns the action with the highest expected final score.
+func (s *Solver) BestAction(gs *game.GameState) (game.Action, error) {
+	values, err := s.Evaluate(gs)
+	if err != nil {
+		return game.Action{}, err
+	}
+
+	return values[0].Action, nil
+}
+
+// usedMask returns the bitmask of the categories that have been used in the game state.
+func usedMask(gs *game.GameState) int {
+	mask := 0
+	for c, cat := range gs.Categories.All() {
+		if cat.IsUsed() {
+			mask |= 1 << c
+		}
+	}
+
+	return mask
+}
+
+// handsOfSize returns every hand with exactly m dice.
+func handsOfSize(m int) []hand {
+	var hands []hand
+
+	var build func(face, left int, h hand)
+	build = func(face, left int, h hand) {
+		if face == numFaces-1 {
+			h[face] = left
+			hands = append(hands, h)
+			return
+		}
+
+		for count := 0; count <= left; count++ {
+			h[face] = count
+			build(face+1, left-count, h)
+		}
+	}
+	build(0, m, hand{})
+
+	return hands
+}
+
+// subHandsOf returns every hand that can be kept from h, including the empty hand and h itself.
+func subHandsOf(h hand) []hand {
+	subs := []hand{{}}
+	for face, count := range h {
+		var next []hand
+		for _, sub := range subs {
+			for c := 0; c <= count; c++ {
+				sub[face] = c
+				next = append(next, sub)
+			}
+		}
+		subs = next
+	}
+
+	return subs
+}
+
+// multinomial returns the probability of rolling exactly the hand h with the provided face probabilities.
+func multinomial(h hand, probs [numFaces]float64) float64 {
+	p := 1.0
+	n := 0
+	for face, count := range h {
+		for k := 1; k <= count; k++ {
+			n++
+			p *= probs[face] * float64(n) / float64(k)
+		}
+	}
+
+	return p
+}
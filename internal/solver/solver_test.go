@@ -0,0 +1,142 @@
+package solver
+
+import (
+	"math"
+	"testing"
+
+	"github.com/iadams749/JumbleBerryFieldsBot/internal/game"
+)
+
+// defaultSolver is shared between tests because solving the default rules takes a moment
+var defaultSolver = New(nil)
+
+func TestSolver_ExpectedScore(t *testing.T) {
+	t.Parallel()
+
+	// with only Moonberries on the dice every category except Jumbleberry, Sugarberry, Pickleberry
+	// and Mixed scores 35
+	onlyMoonberries := game.DefaultRules()
+	onlyMoonberries.FaceWeights = game.FaceWeights{Moonberry: 1}
+
+	tests := []struct {
+		name   string
+		solver *Solver
+		min    float64
+		max    float64
+	}{
+		{
+			name:   "Only Moonberries",
+			solver: New(onlyMoonberries),
+			min:    175,
+			max:    175,
+		},
+		{
+			name:   "Default rules",
+			solver: defaultSolver,
+			min:    121.5,
+			max:    122.1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := tt.solver.ExpectedScore()
+			if got < tt.min-1e-9 || got > tt.max+1e-9 {
+				t.Errorf("ExpectedScore() = %v, want between %v and %v", got, tt.min, tt.max)
+			}
+		})
+	}
+}
+
+func TestSolver_BestAction(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		berries []game.Berry
+		setup   func(gs *game.GameState)
+		want    game.Action
+		wantErr bool
+	}{
+		{
+			name:    "Five Moonberries go in Five of a Kind",
+			berries: []game.Berry{game.Moonberry, game.Moonberry, game.Moonberry, game.Moonberry, game.Moonberry},
+			want:    game.NewScore(game.CatFive),
+		},
+		{
+			name:    "Unrolled jars are rolled",
+			berries: nil,
+			want:    game.NewReroll(),
+		},
+		{
+			name:    "Last category must be scored",
+			berries: []game.Berry{game.Pest, game.Pest, game.Pest, game.Pest, game.Jumbleberry},
+			setup: func(gs *game.GameState) {
+				for _, cat := range gs.Categories.All()[1:] {
+					cat.CalcScore(gs.GetBerries())
+				}
+				gs.RollsLeftInTurn = 0
+			},
+			want: game.NewScore(game.CatJumbleberry),
+		},
+		{
+			name:    "Game over",
+			berries: []game.Berry{game.Pest, game.Pest, game.Pest, game.Pest, game.Pest},
+			setup: func(gs *game.GameState) {
+				gs.RoundsCompleted = game.NumCategories
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			gs := game.NewGameWithRoller(game.DefaultRules(), game.NewFixedRoller(tt.berries...))
+			if tt.berries != nil {
+				gs.RollJars()
+			}
+			if tt.setup != nil {
+				tt.setup(gs)
+			}
+
+			got, err := defaultSolver.BestAction(gs)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("BestAction() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("BestAction() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSolver_Evaluate(t *testing.T) {
+	t.Parallel()
+	gs := game.NewGameWithRoller(game.DefaultRules(), game.NewFixedRoller(game.Jumbleberry, game.Jumbleberry, game.Sugarberry, game.Sugarberry, game.Pest))
+	gs.RollJars()
+
+	values, err := defaultSolver.Evaluate(gs)
+	if err != nil {
+		t.Fatalf("expected nil error but got %v", err)
+	}
+
+	// 9 categories plus 3 * 3 * 2 distinct keeps
+	if len(values) != 9+18 {
+		t.Errorf("expected 27 distinct actions, got %d", len(values))
+	}
+
+	for i := 1; i < len(values); i++ {
+		if values[i].Value > values[i-1].Value {
+			t.Fatalf("actions are not sorted by value")
+		}
+	}
+
+	for _, v := range values {
+		if err := gs.CheckAction(v.Action); err != nil {
+			t.Errorf("solver returned illegal action %s: %v", v.Action, err)
+		}
+	}
+
+	if best := values[0].Value; math.IsNaN(best) || best <= 0 {
+		t.Errorf("invalid best value %v", best)
+	}
+}
@@ -2,40 +2,55 @@ package main
 
 import (
 	"fmt"
-
-	"github.com/MaxHalford/eaopt"
-	"github.com/iadams749/JumbleBerryFieldsBot/internal/genome"
+	"os"
 )
 
-func main() {
-	// Instantiate a GA with a GAConfig
-	var ga, err = eaopt.NewDefaultGAConfig().NewGA()
-	if err != nil {
-		fmt.Println(err)
-		return
-	}
+// usage is printed when no valid subcommand is provided.
+const usage = `usage: JumbleBerryFieldsBot <command> [flags]
 
-	ga.NGenerations = 1000
-	ga.PopSize = 100
-	ga.ParallelEval = true
-	ga.Model = eaopt.ModGenerational{
-		Selector:  eaopt.SelTournament{NContestants: 3},
-		MutRate:   0.2,
-		CrossRate: 0.7,
+commands:
+  train   train a neural network with a genetic algorithm
+  eval    score a saved genome over many seeded games
+  arena   compare players over the same seeded games
+  play    play a game in the terminal, or watch a saved genome play one
+  solve   compute the optimal strategy and its expected score
+  advise  recommend a move for a position from a game on a physical board
+  serve   host games over HTTP with a JSON API
+
+Run "JumbleBerryFieldsBot <command> -h" for the flags of a command.
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
 	}
 
-	// Add a custom print function to track progress
-	ga.Callback = func(ga *eaopt.GA) {
-		var totalFitness float64
-        for _, indiv := range ga.Populations[0].Individuals {
-            totalFitness += indiv.Fitness
-        }
-        avgFitness := totalFitness / float64(len(ga.Populations[0].Individuals))
-        fmt.Printf("Generation %d | Avg Fitness: %f | Best: %f\n", ga.Generations, avgFitness, ga.HallOfFame[0].Fitness)
+	var err error
+	switch cmd, args := os.Args[1], os.Args[2:]; cmd {
+	case "train":
+		err = runTrain(args)
+	case "eval":
+		err = runEval(args)
+	case "arena":
+		err = runArena(args)
+	case "play":
+		err = runPlay(args)
+	case "solve":
+		err = runSolve(args)
+	case "advise":
+		err = runAdvise(args)
+	case "serve":
+		err = runServe(args)
+	case "help", "-h", "--help":
+		fmt.Print(usage)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", cmd, usage)
+		os.Exit(2)
 	}
 
-	// Initialize the GA with the neural network factory
-	if err := ga.Minimize(genome.NewGenomeFactory([]int{128, 128})); err != nil {
-		panic(err)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
 	}
 }
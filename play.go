@@ -0,0 +1,100 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/iadams749/JumbleBerryFieldsBot/internal/console"
+	"github.com/iadams749/JumbleBerryFieldsBot/internal/game"
+	"github.com/iadams749/JumbleBerryFieldsBot/internal/genome"
+)
+
+// runPlay plays a game in the terminal, either with commands typed by the player or by watching a saved genome.
+func runPlay(args []string) error {
+	fs := flag.NewFlagSet("play", flag.ExitOnError)
+	path := fs.String("genome", "", "saved genome to watch, instead of playing yourself")
+	seed := fs.Int64("seed", 0, "seed for the dice, 0 picks a random seed")
+	tracePath := fs.String("trace", "", "JSON file to write the genome's decisions to")
+	logPath := fs.String("log", "", "JSON file to write the event log of the game to, so it can be replayed")
+	fs.Parse(args)
+
+	gs := game.NewGameWithRoller(game.DefaultRules(), newRoller(*seed))
+	if *logPath != "" {
+		if err := gs.EnableLog(); err != nil {
+			return err
+		}
+	}
+
+	var err error
+	if *path == "" {
+		_, err = console.NewSession(gs, os.Stdin, os.Stdout).Run()
+	} else {
+		err = watchGenome(gs, *path, *tracePath)
+	}
+
+	if err != nil || *logPath == "" {
+		return err
+	}
+
+	data, err := json.MarshalIndent(gs.Log, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(*logPath, data, 0o644); err != nil {
+		return err
+	}
+	fmt.Printf("Saved event log to %s\n", *logPath)
+
+	return nil
+}
+
+// watchGenome lets the saved genome at path play the game, printing every decision it makes.
+// If tracePath isn't empty, the decisions are also saved to it.
+func watchGenome(gs *game.GameState, path, tracePath string) error {
+	network, err := loadNetwork(path)
+	if err != nil {
+		return err
+	}
+
+	gs.RollJars()
+
+	// printing the board and every decision the genome makes
+	trace := &genome.Trace{}
+	for !gs.IsOver() {
+		fmt.Println(gs)
+
+		step, err := network.TraceMove(gs)
+		fmt.Println(step)
+		if err != nil {
+			return err
+		}
+		trace.Steps = append(trace.Steps, step)
+	}
+
+	fmt.Println(gs)
+	fmt.Printf("Final score: %d\n", gs.Score)
+
+	if tracePath == "" {
+		return nil
+	}
+
+	trace.FinalScore = gs.Score
+	if err := genome.SaveTrace(tracePath, trace); err != nil {
+		return err
+	}
+	fmt.Printf("Saved trace to %s\n", tracePath)
+
+	return nil
+}
+
+// newRoller returns a Roller for the seed, or a randomly seeded one if the seed is 0.
+func newRoller(seed int64) game.Roller {
+	if seed == 0 {
+		return game.NewRandomRoller()
+	}
+
+	return game.NewSeededRoller(seed)
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/iadams749/JumbleBerryFieldsBot/internal/player"
+	"github.com/iadams749/JumbleBerryFieldsBot/internal/server"
+)
+
+// runServe hosts games over HTTP until the process is stopped.
+func runServe(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := fs.String("addr", "localhost:8080", "address to listen on")
+	path := fs.String("genome", "", "saved genome that suggests moves, suggestions are disabled if empty")
+	ttl := fs.Duration("ttl", 30*time.Minute, "how long an unused game is kept")
+	fs.Parse(args)
+
+	var suggest player.Player
+	if *path != "" {
+		network, err := loadNetwork(*path)
+		if err != nil {
+			return err
+		}
+		suggest = network
+	}
+
+	store := server.NewStore(*ttl)
+
+	// expired games are also removed whenever a game is created, this catches a server that has gone quiet
+	go func() {
+		for range time.Tick(*ttl) {
+			store.Sweep()
+		}
+	}()
+
+	fmt.Printf("Serving games on http://%s\n", *addr)
+	return http.ListenAndServe(*addr, server.New(store, suggest))
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/iadams749/JumbleBerryFieldsBot/internal/game"
+	"github.com/iadams749/JumbleBerryFieldsBot/internal/solver"
+)
+
+// runSolve computes the optimal strategy, prints its expected score and optionally plays games with it.
+func runSolve(args []string) error {
+	fs := flag.NewFlagSet("solve", flag.ExitOnError)
+	rulesPath := fs.String("rules", "", "JSON file with house rules, the standard rules are used if empty")
+	games := fs.Int("games", 0, "number of seeded games to play with the optimal strategy")
+	seed := fs.Int64("seed", 1, "seed of the first game, game i uses seed+i")
+	fs.Parse(args)
+
+	rules := game.DefaultRules()
+	if *rulesPath != "" {
+		var err error
+		if rules, err = game.LoadRules(*rulesPath); err != nil {
+			return err
+		}
+	}
+
+	s := solver.New(rules)
+	fmt.Printf("Optimal expected score: %.4f\n", s.ExpectedScore())
+
+	if *games < 1 {
+		return nil
+	}
+
+	scores := make([]int, *games)
+	for i := range scores {
+		gs := game.NewGameWithRoller(rules, rules.NewRoller(*seed+int64(i)))
+		gs.RollJars()
+
+		for !gs.IsOver() {
+			action, err := s.BestAction(gs)
+			if err != nil {
+				return err
+			}
+
+			if err := gs.Apply(action); err != nil {
+				return err
+			}
+		}
+
+		scores[i] = gs.Score
+	}
+
+	printStats(scores)
+
+	return nil
+}
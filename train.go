@@ -0,0 +1,271 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math/rand"
+
+	"github.com/MaxHalford/eaopt"
+	"github.com/iadams749/JumbleBerryFieldsBot/internal/genome"
+)
+
+// runTrain trains a population of genomes with a genetic algorithm.
+// Hyperparameters come from the defaults, then an optional JSON config file, then any flags that were set.
+func runTrain(args []string) error {
+	fs := flag.NewFlagSet("train", flag.ExitOnError)
+	configPath := fs.String("config", "", "JSON file with the training config")
+	resume := fs.String("resume", "", "resume training from the provided checkpoint file")
+	output := fs.String("out", "best_genome.json", "where the best genome is saved")
+	checkpointPath := fs.String("checkpoint", "checkpoint.json", "where the state of the GA is saved")
+	saveEvery := fs.Uint("save-every", 25, "generations between saves of the best genome and the checkpoint")
+
+	defaults := genome.DefaultTrainingConfig()
+	generations := fs.Int("generations", defaults.Generations, "number of generations")
+	popSize := fs.Int("pop", defaults.PopSize, "population size")
+	hidden := intList(defaults.HiddenLayerSizes)
+	fs.Var(&hidden, "hidden", "comma separated hidden layer sizes")
+	mutRate := fs.Float64("mut", defaults.MutRate, "mutation rate")
+	crossRate := fs.Float64("cross", defaults.CrossRate, "crossover rate")
+	contestants := fs.Int("contestants", defaults.NContestants, "tournament selection size")
+	games := fs.Int("games", defaults.Games, "games each genome plays per generation")
+	stat := fs.String("stat", string(defaults.Statistic), "how the scores of the games are combined: mean, median or min")
+	seed := fs.Int64("seed", defaults.Seed, "seed for the GA, 0 picks a random seed")
+	encoding := fs.String("encoding", string(defaults.Encoding), "network inputs: onehot, or rich to add berry counts, category scores, rounds left and the score")
+	decoding := fs.String("decoding", string(defaults.Decoding), "how outputs become moves: argmax, sigmoid to lock jars at random, or softmax to sample moves")
+	penalty := fs.Float64("penalty", defaults.Penalty, "fitness penalty for every invalid decision per game")
+	acts := activationList(defaults.Activations)
+	fs.Var(&acts, "activations", "comma separated activation of each hidden layer and the output layer: relu, leakyrelu, tanh, sigmoid, identity or softmax (output only)")
+	kind := fs.String("kind", string(defaults.Kind), "genome to train: dense, or neat to evolve the structure of the network too")
+	threshold := fs.Float64("species-threshold", defaults.SpeciesThreshold, "compatibility distance under which NEAT genomes share a species")
+	fs.Parse(args)
+
+	config := defaults
+	if *configPath != "" {
+		var err error
+		if config, err = genome.LoadTrainingConfig(*configPath); err != nil {
+			return err
+		}
+	}
+
+	// flags that were explicitly set override the config file, or the config of the checkpoint when resuming
+	applyFlags := func(config *genome.TrainingConfig) {
+		fs.Visit(func(f *flag.Flag) {
+			switch f.Name {
+			case "generations":
+				config.Generations = *generations
+			case "pop":
+				config.PopSize = *popSize
+			case "hidden":
+				config.HiddenLayerSizes = hidden
+			case "mut":
+				config.MutRate = *mutRate
+			case "cross":
+				config.CrossRate = *crossRate
+			case "contestants":
+				config.NContestants = *contestants
+			case "games":
+				config.Games = *games
+			case "stat":
+				config.Statistic = genome.Statistic(*stat)
+			case "seed":
+				config.Seed = *seed
+			case "encoding":
+				config.Encoding = genome.Encoding(*encoding)
+			case "decoding":
+				config.Decoding = genome.Decoding(*decoding)
+			case "penalty":
+				config.Penalty = *penalty
+			case "activations":
+				config.Activations = acts
+			case "kind":
+				config.Kind = genome.GenomeKind(*kind)
+			case "species-threshold":
+				config.SpeciesThreshold = *threshold
+			}
+		})
+	}
+	applyFlags(&config)
+
+	var checkpoint *genome.Checkpoint
+	if *resume != "" {
+		var err error
+		if checkpoint, err = genome.LoadCheckpoint(*resume); err != nil {
+			return err
+		}
+
+		config = checkpoint.Config
+		applyFlags(&config)
+		fmt.Printf("Resuming from generation %d\n", checkpoint.Generation)
+	}
+
+	enc, err := config.Encoding.NewEncoder()
+	if err != nil {
+		return err
+	}
+
+	if _, err := config.Decoding.NewDecoder(); err != nil {
+		return err
+	}
+
+	if len(config.Activations) > 0 {
+		if err := genome.CheckActivations(config.Activations, len(config.HiddenLayerSizes)+1); err != nil {
+			return err
+		}
+	}
+
+	if config.Penalty < 0 {
+		return fmt.Errorf("penalty must not be negative, got %v", config.Penalty)
+	}
+
+	var factory genome.GenomeFactory
+	var model eaopt.Model
+	var neat *genome.NEATModel
+
+	switch config.Kind {
+	case genome.KindDense, "":
+		factory = genome.NewGenomeFactory(config.HiddenLayerSizes, enc, config.Activations...)
+		model = eaopt.ModGenerational{
+			Selector:  eaopt.SelTournament{NContestants: uint(config.NContestants)},
+			MutRate:   config.MutRate,
+			CrossRate: config.CrossRate,
+		}
+	case genome.KindNEAT:
+		factory = genome.NewNEATFactory(enc, genome.NewInnovations(enc.Size()))
+		neat = &genome.NEATModel{
+			Threshold:    config.SpeciesThreshold,
+			CrossRate:    config.CrossRate,
+			NContestants: uint(config.NContestants),
+		}
+		model = neat
+	default:
+		return fmt.Errorf("unknown genome kind %q", config.Kind)
+	}
+
+	if err := model.Validate(); err != nil {
+		return err
+	}
+
+	factory = factory.WithDecoding(config.Decoding)
+	if checkpoint != nil {
+		factory = checkpoint.Factory()
+	}
+
+	// every genome in a generation plays the same seeded games
+	evalSeed := config.Seed
+	if checkpoint != nil {
+		evalSeed = checkpoint.Seed
+	}
+	if evalSeed == 0 {
+		evalSeed = rand.Int63()
+	}
+
+	eval, err := genome.NewEvaluation(config.Games, config.Statistic, evalSeed)
+	if err != nil {
+		return err
+	}
+	eval.Penalty = config.Penalty
+	factory = factory.WithEvaluation(eval)
+
+	// Instantiate a GA with a GAConfig
+	gaConfig := eaopt.NewDefaultGAConfig()
+	if config.Seed != 0 {
+		gaConfig.RNG = rand.New(rand.NewSource(config.Seed))
+	}
+
+	ga, err := gaConfig.NewGA()
+	if err != nil {
+		return err
+	}
+
+	ga.NGenerations = uint(config.Generations)
+	ga.PopSize = uint(config.PopSize)
+	ga.ParallelEval = true
+	ga.Model = model
+
+	if checkpoint != nil {
+		ga.NPops = uint(len(checkpoint.Populations))
+		ga.NGenerations -= min(ga.NGenerations, checkpoint.Generation)
+	}
+
+	// lastSaved is the generation the best genome was last saved at, so it isn't saved twice at the end
+	lastSaved := -1
+
+	// Add a custom print function to track progress
+	ga.Callback = func(ga *eaopt.GA) {
+		// the first callback happens right after the populations are initialized from the checkpoint
+		if checkpoint != nil {
+			checkpoint.Restore(ga, eval)
+			checkpoint = nil
+			if err := eval.NextGeneration(ga); err != nil {
+				fmt.Println(err)
+			}
+			return
+		}
+
+		var totalFitness float64
+		for _, indiv := range ga.Populations[0].Individuals {
+			totalFitness += indiv.Fitness
+		}
+		avgFitness := totalFitness / float64(len(ga.Populations[0].Individuals))
+		fmt.Printf("Generation %d | Avg Fitness: %f | Best: %f | Stalled: %d", ga.Generations, avgFitness, ga.HallOfFame[0].Fitness, eval.Stalled())
+		if best, ok := ga.HallOfFame[0].Genome.(*genome.NEATGenome); ok {
+			species, err := neat.Species(ga.Populations[0].Individuals)
+			if err != nil {
+				fmt.Println(err)
+			}
+			fmt.Printf(" | Species: %d | Best size: %d hidden, %d connections", species, best.HiddenNodes(), best.EnabledConns())
+		}
+		fmt.Println()
+
+		if *saveEvery > 0 && ga.Generations > 0 && ga.Generations%*saveEvery == 0 {
+			saveBest(ga, config, *output)
+			lastSaved = int(ga.Generations)
+
+			if err := genome.SaveCheckpoint(*checkpointPath, ga, config, eval); err != nil {
+				fmt.Println(err)
+			}
+		}
+
+		if err := eval.NextGeneration(ga); err != nil {
+			fmt.Println(err)
+		}
+	}
+
+	// Initialize the GA with the neural network factory
+	if err := ga.Minimize(factory); err != nil {
+		return err
+	}
+
+	if int(ga.Generations) != lastSaved {
+		saveBest(ga, config, *output)
+	}
+
+	return nil
+}
+
+// saveBest saves the best genome in the hall of fame to path.
+func saveBest(ga *eaopt.GA, config genome.TrainingConfig, path string) {
+	best := ga.HallOfFame[0]
+
+	info := genome.TrainingInfo{
+		Config:     config,
+		Generation: ga.Generations,
+		Fitness:    best.Fitness,
+	}
+
+	var err error
+	switch g := best.Genome.(type) {
+	case *genome.Genome:
+		err = genome.Save(path, g, info)
+	case *genome.NEATGenome:
+		err = genome.SaveNEAT(path, g, info)
+	default:
+		err = fmt.Errorf("can't save genome of type %T", best.Genome)
+	}
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	fmt.Printf("Saved best genome to %s\n", path)
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/iadams749/JumbleBerryFieldsBot/internal/genome"
+)
+
+// trainArgs returns the arguments of a small, seeded training run that saves to dir.
+func trainArgs(dir string, extra ...string) []string {
+	args := []string{
+		"-pop", "6", "-hidden", "4", "-games", "2", "-seed", "3", "-save-every", "2",
+		"-out", filepath.Join(dir, "best.json"),
+		"-checkpoint", filepath.Join(dir, "checkpoint.json"),
+	}
+	return append(args, extra...)
+}
+
+// bestInfo returns the training info of the best genome saved in dir.
+func bestInfo(t *testing.T, dir string) *genome.TrainingInfo {
+	t.Helper()
+	_, info, err := genome.Load(filepath.Join(dir, "best.json"))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	return info
+}
+
+func TestRunTrain_ResumeMoreGenerations(t *testing.T) {
+	dir := t.TempDir()
+	if err := runTrain(trainArgs(dir, "-generations", "3")); err != nil {
+		t.Fatalf("runTrain() error = %v", err)
+	}
+
+	resumed := t.TempDir()
+	args := trainArgs(resumed, "-generations", "10", "-resume", filepath.Join(dir, "checkpoint.json"))
+	if err := runTrain(args); err != nil {
+		t.Fatalf("runTrain() resume error = %v", err)
+	}
+
+	if info := bestInfo(t, resumed); info.Generation != 10 || info.Config.Generations != 10 {
+		t.Errorf("resumed run ended at generation %d of %d, want 10 of 10", info.Generation, info.Config.Generations)
+	}
+}
+
+// captureStdout returns what run prints to standard output.
+func captureStdout(t *testing.T, run func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Pipe() error = %v", err)
+	}
+
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	out := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		out <- string(data)
+	}()
+
+	run()
+	w.Close()
+	return <-out
+}
+
+func TestRunTrain_SavesBestOnce(t *testing.T) {
+	tests := []struct {
+		name        string
+		generations string
+		want        int
+	}{
+		{name: "Last generation saved", generations: "4", want: 2},
+		{name: "Last generation not saved", generations: "3", want: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var err error
+			out := captureStdout(t, func() {
+				err = runTrain(trainArgs(t.TempDir(), "-generations", tt.generations))
+			})
+			if err != nil {
+				t.Fatalf("runTrain() error = %v", err)
+			}
+
+			if saves := strings.Count(out, "Saved best genome"); saves != tt.want {
+				t.Errorf("runTrain() saved the best genome %d times, want %d", saves, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunTrain_ResumeMatchesUninterrupted(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "Dense", args: []string{"-kind", "dense"}},
+		// enough NEAT genomes, with a low enough threshold, to split connections and species before generation 2
+		{name: "NEAT", args: []string{"-kind", "neat", "-pop", "30", "-species-threshold", "1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uninterrupted := t.TempDir()
+			if err := runTrain(trainArgs(uninterrupted, append(tt.args, "-generations", "4")...)); err != nil {
+				t.Fatalf("runTrain() error = %v", err)
+			}
+
+			// a run stopped at generation 2, then resumed up to generation 4
+			stopped := t.TempDir()
+			if err := runTrain(trainArgs(stopped, append(tt.args, "-generations", "2")...)); err != nil {
+				t.Fatalf("runTrain() error = %v", err)
+			}
+
+			resumed := t.TempDir()
+			args := trainArgs(resumed, append(tt.args, "-generations", "4", "-resume", filepath.Join(stopped, "checkpoint.json"))...)
+			if err := runTrain(args); err != nil {
+				t.Fatalf("runTrain() resume error = %v", err)
+			}
+
+			// both runs checkpoint generation 4, and must have bred and scored the same population and hall of fame
+			wantCheckpoint := loadCheckpoint(t, uninterrupted)
+			gotCheckpoint := loadCheckpoint(t, resumed)
+			for i, want := range wantCheckpoint.Populations[0] {
+				if got := gotCheckpoint.Populations[0][i]; !reflect.DeepEqual(got, want) {
+					t.Fatalf("resumed individual %d has fitness %v, want %v", i, got.Fitness, want.Fitness)
+				}
+			}
+
+			want, got := wantCheckpoint.HallOfFame[0], gotCheckpoint.HallOfFame[0]
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("resumed run's best has fitness %v, want %v", got.Fitness, want.Fitness)
+			}
+
+			// NEAT runs must also have numbered the same mutations and kept the same species
+			if !reflect.DeepEqual(gotCheckpoint.Innovations, wantCheckpoint.Innovations) {
+				t.Errorf("resumed run has different innovations")
+			}
+			if !reflect.DeepEqual(gotCheckpoint.Species, wantCheckpoint.Species) {
+				t.Errorf("resumed run has %d species, want %d", len(gotCheckpoint.Species), len(wantCheckpoint.Species))
+			}
+		})
+	}
+}
+
+// loadCheckpoint returns the checkpoint saved in dir.
+func loadCheckpoint(t *testing.T, dir string) *genome.Checkpoint {
+	t.Helper()
+	checkpoint, err := genome.LoadCheckpoint(filepath.Join(dir, "checkpoint.json"))
+	if err != nil {
+		t.Fatalf("LoadCheckpoint() error = %v", err)
+	}
+	if checkpoint.Generation != 4 {
+		t.Fatalf("checkpoint is at generation %d, want 4", checkpoint.Generation)
+	}
+	return checkpoint
+}